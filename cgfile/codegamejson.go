@@ -21,6 +21,16 @@ type CodeGameFileData struct {
 	LangConfig  map[string]any   `json:"lang_config,omitempty"`
 	GameURL     string           `json:"game_url,omitempty"`
 	ModVersion  versions.Version `json:"mod_version,omitempty"`
+	ModOverride *ModuleOverride  `json:"mod_override,omitempty"`
+}
+
+// ModuleOverride replaces the module used for a project.
+// If Path is set, the module executable at Path is used instead of an installed version.
+// Otherwise Version pins the module version.
+type ModuleOverride struct {
+	Version versions.Version `json:"version,omitempty"`
+	// relative paths are relative to the project root
+	Path string `json:"path,omitempty"`
 }
 
 func Load(projectRoot string) (*CodeGameFileData, error) {
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/mattn/go-colorable"
@@ -53,9 +54,14 @@ type progressBar struct {
 	update chan any
 }
 
-var progressBars = make(map[string]progressBar)
+var (
+	progressBars   = make(map[string]progressBar)
+	progressBarsMu sync.Mutex
+)
 
 func Progress(key, message string, current, total int64, unit Unit) {
+	progressBarsMu.Lock()
+	defer progressBarsMu.Unlock()
 	if b, ok := progressBars[key]; ok {
 		b.bar.SetCurrent(current)
 		b.update <- struct{}{}
@@ -87,6 +93,8 @@ func Progress(key, message string, current, total int64, unit Unit) {
 }
 
 func CancelProgressBars() {
+	progressBarsMu.Lock()
+	defer progressBarsMu.Unlock()
 	for _, b := range progressBars {
 		b.bar.Abort(false)
 	}
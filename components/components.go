@@ -11,20 +11,30 @@ import (
 const FeedbackPkg = feedback.Package("components")
 
 func CGEParser(cgeVersion versions.Version) (binPath string, err error) {
-	return component("cge-parser", cgeVersion)
+	return Component("cge-parser", cgeVersion)
 }
 
 func CGDebug(cgVersion versions.Version) (binPath string, err error) {
-	return component("cg-debug", cgVersion)
+	return Component("cg-debug", cgVersion)
 }
 
-func component(name string, version versions.Version) (string, error) {
+// Component returns the path of the binary of the component with name, which supports version, and installs it if necessary.
+// Components are declared in the components config file in addition to the built-in components.
+func Component(name string, version versions.Version) (string, error) {
 	compatibleOverride, binPath, err := findLatestCompatibleVersionSupportedByComponentInOverrides(name, version)
 	if err != nil && !errors.Is(err, ErrVersionNotFound) {
 		return "", fmt.Errorf("find latest compatible version supported by %s in overrides: %s", name, err)
 	}
 
-	comp, sup, err := findLatestCompatibleVersionSupportedByComponent(name, version)
+	cfg, err := loadComponentConfig(name)
+	if err != nil {
+		if compatibleOverride != nil {
+			return binPath, nil
+		}
+		return "", err
+	}
+
+	comp, sup, err := findLatestCompatibleVersionSupportedByComponent(name, cfg, version)
 	if err != nil {
 		if compatibleOverride != nil {
 			return binPath, nil
@@ -35,5 +45,5 @@ func component(name string, version versions.Version) (string, error) {
 		return binPath, nil
 	}
 
-	return install(name, comp)
+	return install(name, cfg, comp)
 }
@@ -1,15 +1,14 @@
 package components
 
 import (
-	"archive/tar"
-	"archive/zip"
-	"bytes"
-	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -17,9 +16,8 @@ import (
 
 	"github.com/adrg/xdg"
 
-	"github.com/code-game-project/cli-utils/cli"
 	"github.com/code-game-project/cli-utils/feedback"
-	"github.com/code-game-project/cli-utils/request"
+	"github.com/code-game-project/cli-utils/modules"
 	"github.com/code-game-project/cli-utils/versions"
 )
 
@@ -28,10 +26,13 @@ var (
 	ErrVersionNotFound = errors.New("version not found")
 )
 
-func findLatestCompatibleVersionSupportedByComponent(componentName string, version versions.Version) (component, supported versions.Version, err error) {
-	versionMap, err := request.FetchJSON[map[string]versions.Version](fmt.Sprintf("https://raw.githubusercontent.com/code-game-project/%s/main/versions.json", componentName), 24*time.Hour)
+// maximum duration of the '--version' smoke check of a downloaded component binary
+var smokeCheckTimeout = 10 * time.Second
+
+func findLatestCompatibleVersionSupportedByComponent(componentName string, cfg componentConfig, version versions.Version) (component, supported versions.Version, err error) {
+	versionMap, err := cfg.versionMap()
 	if err != nil {
-		return nil, nil, fmt.Errorf("fetch version map: %w", err)
+		return nil, nil, fmt.Errorf("load version map: %w", err)
 	}
 	for sup, comp := range versionMap {
 		s, err := versions.Parse(sup)
@@ -72,130 +73,87 @@ func findLatestCompatibleVersionSupportedByComponentInOverrides(componentName st
 	return supported, binPath, nil
 }
 
-func install(componentName string, version versions.Version) (string, error) {
+// install downloads the component binary for version from the source in cfg unless a verified binary is already installed.
+// The binary is downloaded and checked in a temporary file, which is renamed to the final path afterwards,
+// so that an interrupted install never leaves a broken binary behind.
+func install(componentName string, cfg componentConfig, version versions.Version) (string, error) {
 	dirName := filepath.Join(componentBinPath, componentName)
 	err := os.MkdirAll(dirName, 0o755)
 	if err != nil {
 		return "", fmt.Errorf("create component binary directory for %s: %w", componentName, err)
 	}
 
-	tag, err := findGitHubTagByVersion(componentName, version)
+	source, err := cfg.newSource()
 	if err != nil {
-		return "", fmt.Errorf("find compatible tag for %s: %w", componentName, err)
+		return "", fmt.Errorf("component '%s': %w", componentName, err)
 	}
 
-	binPath := filepath.Join(dirName, strings.ReplaceAll(strings.TrimPrefix(tag, "v"), ".", "-"))
-	if runtime.GOOS == "windows" {
-		binPath += ".exe"
-	}
-	if _, err = os.Stat(binPath); err == nil {
-		return binPath, nil
+	exactVersion, err := source.FindExactVersion(version)
+	if err != nil {
+		return "", fmt.Errorf("determine exact version of %s: %w", componentName, err)
 	}
 
-	downloadFileName := fmt.Sprintf("%s-%s-%s.tar.gz", componentName, runtime.GOOS, runtime.GOARCH)
+	binPath := filepath.Join(dirName, strings.ReplaceAll(exactVersion.String(), ".", "-"))
 	if runtime.GOOS == "windows" {
-		downloadFileName = fmt.Sprintf("%s-%s-%s.zip", componentName, runtime.GOOS, runtime.GOARCH)
+		binPath += ".exe"
 	}
-
-	feedback.InterceptProgress(request.FeedbackPkg, func(_ feedback.Package, _, _ string, current, total int64, unit cli.Unit) {
-		feedback.Progress(FeedbackPkg, fmt.Sprintf("download %s", componentName), fmt.Sprintf("Downloading component %s", componentName), current, total, unit)
-	})
-	file, err := request.FetchFile(fmt.Sprintf("https://github.com/code-game-project/%s/releases/download/%s/%s", componentName, tag, downloadFileName), 0, true)
-	defer feedback.UninterceptProgress(request.FeedbackPkg)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return "", fmt.Errorf("download %s: %w", componentName, err)
+	if isInstalled(binPath, modules.PublicSource(cfg.Source)) {
+		return binPath, nil
 	}
-	defer file.Close()
 
+	pattern := filepath.Base(strings.TrimSuffix(binPath, ".exe")) + "-*.temp"
 	if runtime.GOOS == "windows" {
-		err = unzipFile(file, componentName+".exe", binPath)
-	} else {
-		err = untargzFile(file, componentName, binPath)
+		pattern += ".exe"
 	}
+	file, err := os.CreateTemp(dirName, pattern)
 	if err != nil {
-		return "", fmt.Errorf("uncompress %s: %w", componentName, err)
+		return "", fmt.Errorf("create component binary file for %s: %w", componentName, err)
 	}
-	return binPath, nil
-}
+	tempBinPath := file.Name()
+	defer os.Remove(tempBinPath)
 
-func findGitHubTagByVersion(componentName string, version versions.Version) (string, error) {
-	type response []struct {
-		Name string `json:"name"`
-	}
-	res, err := request.FetchJSON[response](fmt.Sprintf("https://api.github.com/repos/code-game-project/%s/tags", componentName), 24*time.Hour)
+	hash := sha256.New()
+	err = source.DownloadModuleBinary(io.MultiWriter(file, hash), exactVersion)
+	file.Close()
 	if err != nil {
-		return "", fmt.Errorf("find GitHub tag by version: %w", err)
-	}
-	for _, tag := range res {
-		if strings.HasPrefix(tag.Name, "v"+version.String()) {
-			return tag.Name, nil
-		}
+		return "", fmt.Errorf("download %s: %w", componentName, err)
 	}
-	return "", ErrVersionNotFound
-}
-
-// untargzFile first decompresses source with gzip, then extracts the file with fileName into outputFileName.
-func untargzFile(source io.Reader, fileName, outputFileName string) error {
-	archive, err := gzip.NewReader(source)
+	err = os.Chmod(tempBinPath, 0o755)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("create component binary file for %s: %w", componentName, err)
 	}
-	defer archive.Close()
-
-	tarReader := tar.NewReader(archive)
 
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
-		}
-
-		info := header.FileInfo()
-		if !info.IsDir() && info.Name() == fileName {
-			file, err := os.OpenFile(outputFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode())
-			if err != nil {
-				return err
-			}
-			defer file.Close()
-			_, err = io.Copy(file, tarReader)
-			return err
-		}
+	err = smokeCheck(tempBinPath)
+	if err != nil {
+		return "", fmt.Errorf("check %s: %w", componentName, err)
 	}
 
-	return errors.New("file not found")
-}
-
-// unzipFile first decompresses source with gzip, then extracts the file with fileName into outputFileName.
-func unzipFile(source io.Reader, fileName, outputFileName string) error {
-	data, err := ioutil.ReadAll(source)
+	err = os.Rename(tempBinPath, binPath)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("create component binary file for %s: %w", componentName, err)
 	}
 
-	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	// The manifest is written after the binary is in place, so that it never describes a binary which does not exist.
+	// Without a manifest the binary is downloaded again the next time.
+	err = writeInstallManifest(binPath, installManifest{
+		Source:      modules.PublicSource(cfg.Source),
+		Version:     exactVersion,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		InstalledAt: time.Now().UTC(),
+	})
 	if err != nil {
-		return err
+		feedback.Warn(FeedbackPkg, "Failed to write the install manifest of %s: %s", componentName, err)
 	}
+	return binPath, nil
+}
 
-	for _, f := range reader.File {
-		if !f.FileInfo().IsDir() && f.FileInfo().Name() == fileName {
-			out, err := os.OpenFile(outputFileName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, f.FileInfo().Mode())
-			if err != nil {
-				return err
-			}
-			defer out.Close()
-			in, err := f.Open()
-			if err != nil {
-				return err
-			}
-			defer in.Close()
-
-			_, err = io.Copy(out, in)
-			return err
-		}
+// smokeCheck runs the component binary at binPath with '--version' to ensure that it can be executed.
+func smokeCheck(binPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), smokeCheckTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, binPath, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("run '--version': %w: %s", err, strings.TrimSpace(string(output)))
 	}
-
-	return errors.New("file not found")
+	return nil
 }
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/modules"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// setupComponents writes config to a temporary components config file and installs components into a temporary directory.
+func setupComponents(t *testing.T, config string) (configDir string) {
+	t.Helper()
+	configDir = t.TempDir()
+	oldConfigPath, oldBinPath, oldOverrides := componentsConfigPath, componentBinPath, componentOverrides
+	componentsConfigPath = filepath.Join(configDir, "components.json")
+	componentBinPath = filepath.Join(t.TempDir(), "components")
+	componentOverrides = map[string]map[string]string{}
+	t.Cleanup(func() {
+		componentsConfigPath, componentBinPath, componentOverrides = oldConfigPath, oldBinPath, oldOverrides
+	})
+	err := os.WriteFile(componentsConfigPath, []byte(config), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configDir
+}
+
+// writeLayout writes an OCI image layout to dir, which contains the component binary content tagged with tag.
+// It returns the path of the layer blob.
+func writeLayout(t *testing.T, dir, tag string, content []byte) string {
+	t.Helper()
+	writeBlob := func(data []byte) (string, string) {
+		sum := sha256.Sum256(data)
+		path := filepath.Join(dir, "blobs", "sha256", hex.EncodeToString(sum[:]))
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, data, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "sha256:" + hex.EncodeToString(sum[:]), path
+	}
+	marshal := func(v any) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	layerDigest, layerPath := writeBlob(content)
+	manifest := marshal(map[string]any{
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"layers":    []any{map[string]any{"mediaType": "application/octet-stream", "digest": layerDigest, "size": len(content)}},
+	})
+	manifestDigest, _ := writeBlob(manifest)
+	index := marshal(map[string]any{
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": []any{map[string]any{
+			"mediaType":   "application/vnd.oci.image.manifest.v1+json",
+			"digest":      manifestDigest,
+			"size":        len(manifest),
+			"annotations": map[string]string{"org.opencontainers.image.ref.name": tag},
+		}},
+	})
+	err := os.WriteFile(filepath.Join(dir, "index.json"), index, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layerPath
+}
+
+const forkConfig = `{
+	"cge-parser": {
+		"source": {"provider": "oci", "layout": "fork"},
+		"versions": {"0.3": "0.3", "0.4": "0.4"}
+	}
+}`
+
+func TestComponent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test component is a shell script")
+	}
+	configDir := setupComponents(t, forkConfig)
+	script := []byte("#!/bin/sh\necho 0.4.1-patched\n")
+	writeLayout(t, filepath.Join(configDir, "fork"), "0.4.1", script)
+
+	binPath, err := CGEParser(versions.MustParse("0.4"))
+	if err != nil {
+		t.Fatalf("CGEParser: %s", err)
+	}
+	if filepath.Base(binPath) != "0-4-1" {
+		t.Errorf("binPath = %s, want .../0-4-1", binPath)
+	}
+	content, err := os.ReadFile(binPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(script) {
+		t.Errorf("installed %q, want %q", content, script)
+	}
+
+	// truncated binary of an interrupted install by an older version
+	err = os.WriteFile(binPath, []byte("#!/bin/sh\nech"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CGEParser(versions.MustParse("0.4"))
+	if err != nil {
+		t.Fatalf("CGEParser with corrupt binary: %s", err)
+	}
+	content, _ = os.ReadFile(binPath)
+	if string(content) != string(script) {
+		t.Errorf("corrupt binary was not reinstalled: %q", content)
+	}
+}
+
+func TestComponent_Invalid(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test component is a shell script")
+	}
+	tests := []struct {
+		name    string
+		script  string
+		tamper  bool
+		wantErr error
+	}{
+		{"digest mismatch", "#!/bin/sh\necho 0.4.1\n", true, modules.ErrDigestMismatch},
+		{"smoke check fails", "#!/bin/sh\nexit 1\n", false, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configDir := setupComponents(t, forkConfig)
+			layerPath := writeLayout(t, filepath.Join(configDir, "fork"), "0.4.1", []byte(test.script))
+			if test.tamper {
+				err := os.WriteFile(layerPath, []byte("#!/bin/sh\necho evil\n"), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := CGEParser(versions.MustParse("0.4"))
+			if err == nil {
+				t.Fatal("CGEParser succeeded")
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("CGEParser error = %v, want %v", err, test.wantErr)
+			}
+			entries, err := os.ReadDir(filepath.Join(componentBinPath, "cge-parser"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("install left %d files behind", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package components
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// installManifest is stored next to each installed component binary.
+// Binaries without a matching manifest (e.g. leftovers of an interrupted download by an older version of the CLI
+// or binaries from another source) are reinstalled.
+type installManifest struct {
+	Source      map[string]any   `json:"source"`
+	Version     versions.Version `json:"version"`
+	SHA256      string           `json:"sha256"`
+	InstalledAt time.Time        `json:"installed_at"`
+}
+
+func manifestPath(binPath string) string {
+	return strings.TrimSuffix(binPath, ".exe") + ".manifest.json"
+}
+
+func writeInstallManifest(binPath string, manifest installManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath(binPath), data, 0o644)
+}
+
+// isInstalled returns true if the binary at binPath exists, its checksum matches its install manifest
+// and it was installed from source, which must not contain credentials (see modules.PublicSource).
+func isInstalled(binPath string, source map[string]any) bool {
+	data, err := os.ReadFile(manifestPath(binPath))
+	if err != nil {
+		return false
+	}
+	var manifest installManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return false
+	}
+	if !sameSource(manifest.Source, source) {
+		return false
+	}
+
+	file, err := os.Open(binPath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return false
+	}
+	return hex.EncodeToString(hash.Sum(nil)) == manifest.SHA256
+}
+
+// sameSource compares two 'source' objects by their JSON encoding, which has sorted keys.
+func sameSource(a, b map[string]any) bool {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aData, bData)
+}
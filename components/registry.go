@@ -0,0 +1,101 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/code-game-project/cli-utils/config"
+	"github.com/code-game-project/cli-utils/modules"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// componentsConfigPath is the path of the config file declaring the sources of components.
+// Relative paths in the file are resolved relative to the file.
+//
+// Example:
+//
+//	{
+//		"cge-parser": {
+//			"source": {"provider": "github", "owner": "me", "repository": "cge-parser"},
+//			"versions": "https://raw.githubusercontent.com/me/cge-parser/main/versions.json"
+//		}
+//	}
+var componentsConfigPath = filepath.Join(config.ConfigDir(), "components.json")
+
+// componentConfig is an entry of the components config file.
+type componentConfig struct {
+	// 'source' object like in lang_modules.json; like module binaries, release assets and the binary in them are named after
+	// the repository, not the component (e.g. a fork 'me/cge-parser-patched' must ship 'cge-parser-patched' binaries)
+	Source map[string]any `json:"source"`
+	// supported version (e.g. CGE version) -> component version, inline or the location of a JSON file
+	Versions json.RawMessage `json:"versions"`
+}
+
+var builtinComponentNames = []string{"cge-parser", "cg-debug"}
+
+func builtinComponent(name string) (componentConfig, bool) {
+	for _, n := range builtinComponentNames {
+		if n == name {
+			return componentConfig{
+				Source: map[string]any{
+					"provider":   "github",
+					"owner":      "code-game-project",
+					"repository": name,
+				},
+				Versions: json.RawMessage(strconv.Quote(fmt.Sprintf("https://raw.githubusercontent.com/code-game-project/%s/main/versions.json", name))),
+			}, true
+		}
+	}
+	return componentConfig{}, false
+}
+
+// loadComponentConfig returns the config of the component with name.
+// The fields of an entry in the components config file replace the fields of the built-in entry.
+func loadComponentConfig(name string) (componentConfig, error) {
+	cfg, ok := builtinComponent(name)
+
+	data, err := os.ReadFile(componentsConfigPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return componentConfig{}, fmt.Errorf("read components config: %w", err)
+	}
+	if err == nil {
+		var entries map[string]componentConfig
+		err = json.Unmarshal(data, &entries)
+		if err != nil {
+			return componentConfig{}, fmt.Errorf("decode %s: %w", componentsConfigPath, err)
+		}
+		if entry, exists := entries[name]; exists {
+			ok = true
+			if entry.Source != nil {
+				cfg.Source = entry.Source
+			}
+			if entry.Versions != nil {
+				cfg.Versions = entry.Versions
+			}
+		}
+	}
+
+	if !ok {
+		return componentConfig{}, fmt.Errorf("unknown component '%s'", name)
+	}
+	if cfg.Source == nil {
+		return componentConfig{}, fmt.Errorf("component '%s': missing 'source' field", name)
+	}
+	if cfg.Versions == nil {
+		return componentConfig{}, fmt.Errorf("component '%s': missing 'versions' field", name)
+	}
+	return cfg, nil
+}
+
+func (c componentConfig) newSource() (modules.Source, error) {
+	return modules.NewSource(c.Source, componentsConfigPath)
+}
+
+func (c componentConfig) versionMap() (map[string]versions.Version, error) {
+	return modules.LoadJSON[map[string]versions.Version](c.Versions, componentsConfigPath)
+}
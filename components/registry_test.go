@@ -0,0 +1,46 @@
+package components
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadComponentConfig(t *testing.T) {
+	setupComponents(t, `{
+		"cge-parser": {"source": {"provider": "github", "owner": "me", "repository": "cge-parser"}},
+		"cg-lint": {"source": {"provider": "github", "owner": "me", "repository": "cg-lint"}, "versions": "versions.json"},
+		"broken": {"versions": {}}
+	}`)
+
+	parser, err := loadComponentConfig("cge-parser")
+	if err != nil {
+		t.Fatalf("loadComponentConfig(cge-parser): %s", err)
+	}
+	if parser.Source["owner"] != "me" {
+		t.Errorf("cge-parser source = %v, want fork", parser.Source)
+	}
+	if !strings.Contains(string(parser.Versions), "code-game-project/cge-parser") {
+		t.Errorf("cge-parser versions = %s, want built-in version map", parser.Versions)
+	}
+
+	debug, err := loadComponentConfig("cg-debug")
+	if err != nil {
+		t.Fatalf("loadComponentConfig(cg-debug): %s", err)
+	}
+	if debug.Source["owner"] != "code-game-project" {
+		t.Errorf("cg-debug source = %v, want built-in source", debug.Source)
+	}
+
+	_, err = loadComponentConfig("cg-lint")
+	if err != nil {
+		t.Errorf("loadComponentConfig(cg-lint): %s", err)
+	}
+	_, err = loadComponentConfig("broken")
+	if err == nil || !strings.Contains(err.Error(), "missing 'source' field") {
+		t.Errorf("loadComponentConfig(broken) error = %v, want missing source", err)
+	}
+	_, err = loadComponentConfig("unknown")
+	if err == nil {
+		t.Error("loadComponentConfig(unknown) succeeded")
+	}
+}
@@ -2,6 +2,7 @@ package feedback
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/code-game-project/cli-utils/cli"
 )
@@ -33,18 +34,23 @@ var (
 	enabled             bool
 	disabledLogPackages map[Package]int              = make(map[Package]int)
 	interceptProgress   map[Package]ProgressCallback = make(map[Package]ProgressCallback)
+	mu                  sync.RWMutex
 )
 
 func Progress(pkg Package, process, message string, current, total int64, unit cli.Unit) {
-	if !enabled {
+	mu.RLock()
+	pr, ok := interceptProgress[pkg]
+	isEnabled, receiver := enabled, feedbackReceiver
+	mu.RUnlock()
+	if !isEnabled {
 		return
 	}
-	if pr, ok := interceptProgress[pkg]; ok {
+	if ok {
 		if pr != nil {
 			pr(pkg, process, message, current, total, unit)
 		}
 	} else {
-		feedbackReceiver.Progress(pkg, process, message, current, total, unit)
+		receiver.Progress(pkg, process, message, current, total, unit)
 	}
 }
 
@@ -69,13 +75,19 @@ func Fatal(pkg Package, msgFormat string, msgArgs ...any) {
 }
 
 func Log(pkg Package, severity Severity, msgFormat string, msgArgs ...any) {
-	if !enabled || (severity != SeverityDebug && disabledLogPackages[pkg] > 0) {
+	mu.RLock()
+	skip := !enabled || (severity != SeverityDebug && disabledLogPackages[pkg] > 0)
+	receiver := feedbackReceiver
+	mu.RUnlock()
+	if skip {
 		return
 	}
-	feedbackReceiver.Log(pkg, severity, fmt.Sprintf(msgFormat, msgArgs...))
+	receiver.Log(pkg, severity, fmt.Sprintf(msgFormat, msgArgs...))
 }
 
 func Enable(receiver FeedbackReceiver) {
+	mu.Lock()
+	defer mu.Unlock()
 	if receiver != nil {
 		enabled = true
 	}
@@ -83,20 +95,28 @@ func Enable(receiver FeedbackReceiver) {
 }
 
 func Disable() {
+	mu.Lock()
+	defer mu.Unlock()
 	enabled = false
 }
 
 func Reenable() {
+	mu.Lock()
+	defer mu.Unlock()
 	if feedbackReceiver != nil {
 		enabled = true
 	}
 }
 
 func DisableLog(pkg Package) {
+	mu.Lock()
+	defer mu.Unlock()
 	disabledLogPackages[pkg] += 1
 }
 
 func ReenableLog(pkg Package) {
+	mu.Lock()
+	defer mu.Unlock()
 	disabledLogPackages[pkg] -= 1
 	if disabledLogPackages[pkg] == 0 {
 		delete(disabledLogPackages, pkg)
@@ -104,9 +124,13 @@ func ReenableLog(pkg Package) {
 }
 
 func InterceptProgress(target Package, progressCallback ProgressCallback) {
+	mu.Lock()
+	defer mu.Unlock()
 	interceptProgress[target] = progressCallback
 }
 
 func UninterceptProgress(target Package) {
+	mu.Lock()
+	defer mu.Unlock()
 	delete(interceptProgress, target)
 }
@@ -8,6 +8,7 @@ require (
 	github.com/iancoleman/strcase v0.2.0
 	github.com/mattn/go-colorable v0.1.13
 	github.com/vbauerster/mpb/v8 v8.4.0
+	golang.org/x/sys v0.7.0
 	google.golang.org/protobuf v1.30.0
 )
 
@@ -19,7 +20,6 @@ require (
 	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/mgutz/ansi v0.0.0-20200706080929-d51e80ef957d // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
-	golang.org/x/sys v0.7.0 // indirect
 	golang.org/x/term v0.7.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
 )
@@ -0,0 +1,29 @@
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// BuildFakeModule builds the module in modulestest/testdata/fakemodule and returns the path of the executable.
+func BuildFakeModule(t testing.TB) string {
+	t.Helper()
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("build fake module: cannot determine source directory")
+	}
+	path := filepath.Join(t.TempDir(), "fakemodule")
+	if runtime.GOOS == "windows" {
+		path += ".exe"
+	}
+	cmd := exec.Command("go", "build", "-o", path, ".")
+	cmd.Dir = filepath.Join(filepath.Dir(file), "..", "..", "modulestest", "testdata", "fakemodule")
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("build fake module: %s", err)
+	}
+	return path
+}
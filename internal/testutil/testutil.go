@@ -0,0 +1,73 @@
+// Package testutil contains helpers shared by the tests of several packages.
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const isolatedEnv = "CODEGAME_TEST_ISOLATED"
+
+// Main runs the tests of m with all XDG base directories in a temporary directory, so that the tests never read
+// or modify the data, config or cache files of the user. It is meant to be called by TestMain.
+//
+// The paths of these files are determined when the packages are initialized, so the test binary is run again
+// with the modified environment.
+func Main(m *testing.M) {
+	if os.Getenv(isolatedEnv) != "" {
+		os.Exit(m.Run())
+	}
+	code, err := runIsolated()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "run tests in isolated environment:", err)
+	}
+	os.Exit(code)
+}
+
+func runIsolated() (int, error) {
+	dir, err := os.MkdirTemp("", "codegame-test-")
+	if err != nil {
+		return 1, err
+	}
+	defer os.RemoveAll(dir)
+
+	env := append(os.Environ(), isolatedEnv+"=1")
+	// keep using the Go build cache and config of the user for building test executables
+	if os.Getenv("GOCACHE") == "" {
+		if cacheDir, err := os.UserCacheDir(); err == nil {
+			env = append(env, "GOCACHE="+filepath.Join(cacheDir, "go-build"))
+		}
+	}
+	if os.Getenv("GOENV") == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			env = append(env, "GOENV="+filepath.Join(configDir, "go", "env"))
+		}
+	}
+	for _, name := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME", "XDG_RUNTIME_DIR"} {
+		path := filepath.Join(dir, name)
+		err = os.Mkdir(path, 0o700)
+		if err != nil {
+			return 1, err
+		}
+		env = append(env, name+"="+path)
+	}
+
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,244 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/server"
+	"github.com/code-game-project/cli-utils/sessions"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// BotsOptions configures RunBots.
+type BotsOptions struct {
+	GameURL    string
+	GameID     string
+	JoinSecret string
+	Language   string
+	// number of bots
+	Count int
+	// The username of the n-th bot is "<UsernamePrefix>-<run>-<n>", where <run> is a random ID, which is
+	// unique to every call of RunBots. Default: "bot"
+	UsernamePrefix string
+	Args           []string
+	// Every line written by a bot is prefixed with "[<username>] ". Default: os.Stdout
+	Output io.Writer
+}
+
+// Bot is a client process started by RunBots.
+type Bot struct {
+	Session sessions.Session
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Stop kills the process of the bot and waits for it to exit.
+func (b *Bot) Stop() {
+	b.cancel()
+	<-b.done
+}
+
+// Wait waits for the process of the bot to exit.
+// A bot which was stopped does not return an error.
+func (b *Bot) Wait() error {
+	<-b.done
+	return b.err
+}
+
+// Bots is a group of bots started by RunBots.
+type Bots struct {
+	Bots []*Bot
+}
+
+// Stop stops all bots.
+func (b *Bots) Stop() {
+	for _, bot := range b.Bots {
+		bot.cancel()
+	}
+	b.Wait()
+}
+
+// Wait waits for all bots to exit and returns the joined errors of all bots.
+func (b *Bots) Wait() error {
+	errs := make([]error, 0, len(b.Bots))
+	for _, bot := range b.Bots {
+		err := bot.Wait()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", bot.Session.Username, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunBots creates options.Count players in the game, saves their sessions and runs the client project in the current
+// working directory once for every player. The bots do not receive any input (prompts of the module are declined, see
+// ExecOptions.DeclinePrompts) and their output is prefixed with their username.
+// The bots keep running until they exit or ctx is done. When a bot exits, its player is deleted and its session removed.
+func (m *Module) RunBots(ctx context.Context, modVersion versions.Version, options BotsOptions) (*Bots, error) {
+	if options.Count < 1 {
+		return nil, errors.New("at least one bot is required")
+	}
+	if options.UsernamePrefix == "" {
+		options.UsernamePrefix = "bot"
+	}
+	if options.Output == nil {
+		options.Output = os.Stdout
+	}
+
+	resolution, info, err := m.prepare(modVersion, ActionRunClient)
+	if err != nil {
+		return nil, err
+	}
+	err = checkProjectType(info, ProjectTypeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	runID := make([]byte, 3)
+	_, err = rand.Read(runID)
+	if err != nil {
+		return nil, fmt.Errorf("generate run ID: %w", err)
+	}
+
+	botSessions := make([]sessions.Session, 0, options.Count)
+	for i := 1; i <= options.Count; i++ {
+		username := fmt.Sprintf("%s-%s-%d", options.UsernamePrefix, hex.EncodeToString(runID), i)
+		playerID, playerSecret, err := server.CreatePlayer(options.GameURL, options.GameID, username, options.JoinSecret)
+		if err != nil {
+			removeBotSessions(botSessions)
+			return nil, fmt.Errorf("%s: %w", username, err)
+		}
+		session := sessions.NewSession(options.GameURL, username, options.GameID, playerID, playerSecret)
+		botSessions = append(botSessions, session)
+		err = session.Save()
+		if err != nil {
+			removeBotSessions(botSessions)
+			return nil, fmt.Errorf("save session of %s: %w", username, err)
+		}
+	}
+
+	var outputMu sync.Mutex
+	bots := &Bots{
+		Bots: make([]*Bot, len(botSessions)),
+	}
+	for i, session := range botSessions {
+		botCtx, cancel := context.WithCancel(ctx)
+		bot := &Bot{
+			Session: session,
+			cancel:  cancel,
+			done:    make(chan struct{}),
+		}
+		bots.Bots[i] = bot
+
+		output := &prefixWriter{
+			prefix: []byte("[" + session.Username + "] "),
+			out:    options.Output,
+			mu:     &outputMu,
+		}
+		playerID := session.PlayerID
+		playerSecret := session.PlayerSecret
+		go func() {
+			defer close(bot.done)
+			defer removeBotSessions([]sessions.Session{bot.Session})
+			defer cancel()
+			_, err := m.executePrepared(resolution.Path, info, ActionRunClient, &ActionRunClientData{
+				GameURL:      options.GameURL,
+				Language:     options.Language,
+				Args:         options.Args,
+				GameID:       options.GameID,
+				PlayerID:     &playerID,
+				PlayerSecret: &playerSecret,
+			}, ExecOptions{
+				Context:          botCtx,
+				KillProcessGroup: true,
+				Stdin:            bytes.NewReader(nil),
+				Stdout:           output,
+				Stderr:           output,
+				DeclinePrompts:   true,
+			})
+			output.flush()
+			if botCtx.Err() == nil {
+				bot.err = err
+			}
+		}()
+	}
+	return bots, nil
+}
+
+// removeBotSessions deletes the players of botSessions from the game and removes the sessions.
+func removeBotSessions(botSessions []sessions.Session) {
+	for _, s := range botSessions {
+		err := server.DeletePlayer(s.GameURL, s.GameID, s.PlayerID, s.PlayerSecret)
+		if err != nil {
+			feedback.Warn(FeedbackPkg, "Failed to delete player %s: %s", s.Username, err)
+		}
+		err = s.Remove()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			feedback.Warn(FeedbackPkg, "Failed to remove session of %s: %s", s.Username, err)
+		}
+	}
+}
+
+// maxBotLineLength is the maximum length of a line written by a bot. Longer lines are split.
+const maxBotLineLength = 64 * 1024
+
+// prefixWriter prefixes every line with prefix and writes complete lines to out.
+// Lines longer than maxBotLineLength are split, so that the buffer does not grow without bound.
+type prefixWriter struct {
+	prefix []byte
+	out    io.Writer
+	mu     *sync.Mutex // shared between all writers of out
+
+	bufMu sync.Mutex
+	buf   []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.bufMu.Lock()
+	defer p.bufMu.Unlock()
+	p.buf = append(p.buf, data...)
+	for {
+		var line []byte
+		if i := bytes.IndexByte(p.buf, '\n'); i >= 0 {
+			line = p.buf[:i+1]
+			p.buf = p.buf[i+1:]
+		} else if len(p.buf) >= maxBotLineLength {
+			line = append(p.buf[:maxBotLineLength:maxBotLineLength], '\n')
+			p.buf = p.buf[maxBotLineLength:]
+		} else {
+			break
+		}
+		err := p.writeLine(line)
+		if err != nil {
+			return len(data), err
+		}
+	}
+	return len(data), nil
+}
+
+// flush writes the last incomplete line.
+func (p *prefixWriter) flush() {
+	p.bufMu.Lock()
+	defer p.bufMu.Unlock()
+	if len(p.buf) > 0 {
+		p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.out.Write(append(append([]byte{}, p.prefix...), line...))
+	return err
+}
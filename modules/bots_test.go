@@ -0,0 +1,310 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/code-game-project/cli-utils/internal/testutil"
+	"github.com/code-game-project/cli-utils/sessions"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// newFakeModule returns the fake module loaded with a local source.
+func newFakeModule(t *testing.T) *Module {
+	t.Helper()
+	registry := newTestRegistry(t, fmt.Sprintf(`{
+		"fake": {
+			"display_name": "Fake",
+			"source": {"provider": "local", "path": %q},
+			"codegame_to_library_versions": {"client": {"0.9": "0.1"}}
+		}
+	}`, testutil.BuildFakeModule(t)))
+	m, err := registry.LoadModule("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// fakeGameServer is a game server, which creates and deletes players.
+type fakeGameServer struct {
+	mu      sync.Mutex
+	players map[string]string // id -> username
+	deleted []string
+	// creating the n-th player fails
+	failCount int
+}
+
+func newFakeGameServer(t *testing.T) (*fakeGameServer, string) {
+	t.Helper()
+	s := &fakeGameServer{players: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/games/game/players":
+			var body struct {
+				Username string `json:"username"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if s.failCount > 0 && len(s.players)+1 == s.failCount {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			id := fmt.Sprintf("player%d", len(s.players)+1)
+			s.players[id] = body.Username
+			fmt.Fprintf(w, `{"player_id": %q, "player_secret": "secret"}`, id)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/games/game/players/"):
+			if r.URL.Query().Get("player_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			s.deleted = append(s.deleted, strings.TrimPrefix(r.URL.Path, "/api/games/game/players/"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return s, strings.TrimPrefix(server.URL, "http://")
+}
+
+func removeSessions(t *testing.T, bots *Bots) {
+	t.Cleanup(func() {
+		for _, b := range bots.Bots {
+			b.Session.Remove()
+		}
+	})
+}
+
+func TestModule_RunBots(t *testing.T) {
+	m := newFakeModule(t)
+	server, gameURL := newFakeGameServer(t)
+
+	var output bytes.Buffer
+	bots, err := m.RunBots(context.Background(), versions.MustParse("0.1.0"), BotsOptions{
+		GameURL: gameURL,
+		GameID:  "game",
+		Count:   2,
+		Args:    []string{"arg"},
+		Output:  &output,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removeSessions(t, bots)
+	err = bots.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, b := range bots.Bots {
+		if !regexp.MustCompile(`^bot-[0-9a-f]{6}-` + strconv.Itoa(i+1) + `$`).MatchString(b.Session.Username) {
+			t.Errorf("username = %q, want bot-<run>-%d", b.Session.Username, i+1)
+		}
+		line := fmt.Sprintf("[%s] player %s arg\n", b.Session.Username, b.Session.PlayerID)
+		if !strings.Contains(output.String(), line) {
+			t.Errorf("output = %q, want line %q", output.String(), line)
+		}
+		if _, err := sessions.LoadSession(gameURL, b.Session.PlayerID); err == nil {
+			t.Errorf("session of %s was not removed", b.Session.Username)
+		}
+	}
+	sort.Strings(server.deleted)
+	if !reflect.DeepEqual(server.deleted, []string{"player1", "player2"}) {
+		t.Errorf("deleted players = %v, want [player1 player2]", server.deleted)
+	}
+}
+
+func TestModule_RunBots_UniqueUsernames(t *testing.T) {
+	m := newFakeModule(t)
+	_, gameURL := newFakeGameServer(t)
+
+	usernames := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		bots, err := m.RunBots(context.Background(), versions.MustParse("0.1.0"), BotsOptions{
+			GameURL: gameURL,
+			GameID:  "game",
+			Count:   1,
+			Output:  &bytes.Buffer{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		removeSessions(t, bots)
+		bots.Wait()
+		usernames[bots.Bots[0].Session.Username] = true
+	}
+	if len(usernames) != 2 {
+		t.Errorf("usernames = %v, want two different usernames", usernames)
+	}
+}
+
+func TestModule_RunBots_DeclinePrompts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no feedback channel on Windows")
+	}
+	oldYesNo := askYesNo
+	askYesNo = func(question string, defaultValue bool) bool {
+		t.Errorf("bot asked the user: %s", question)
+		return true
+	}
+	defer func() {
+		askYesNo = oldYesNo
+	}()
+
+	m := newFakeModule(t)
+	_, gameURL := newFakeGameServer(t)
+
+	var output bytes.Buffer
+	bots, err := m.RunBots(context.Background(), versions.MustParse("0.1.0"), BotsOptions{
+		GameURL: gameURL,
+		GameID:  "game",
+		Count:   1,
+		Args:    []string{"prompt"},
+		Output:  &output,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removeSessions(t, bots)
+	err = bots.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "continue false\n") {
+		t.Errorf("output = %q, want declined prompt", output.String())
+	}
+}
+
+func TestModule_RunBots_CreatePlayerFailed(t *testing.T) {
+	m := newFakeModule(t)
+	server, gameURL := newFakeGameServer(t)
+	server.failCount = 2
+
+	_, err := m.RunBots(context.Background(), versions.MustParse("0.1.0"), BotsOptions{
+		GameURL: gameURL,
+		GameID:  "game",
+		Count:   3,
+		Output:  &bytes.Buffer{},
+	})
+	if err == nil {
+		t.Fatal("RunBots succeeded, want error")
+	}
+	if len(server.deleted) != 1 || server.deleted[0] != "player1" {
+		t.Errorf("deleted players = %v, want [player1]", server.deleted)
+	}
+	if _, err := sessions.LoadSession(gameURL, "player1"); err == nil {
+		t.Error("session of the first bot was not removed")
+	}
+}
+
+func TestBots_Stop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not supported on Windows")
+	}
+	oldWaitDelay := processWaitDelay
+	processWaitDelay = time.Minute
+	defer func() {
+		processWaitDelay = oldWaitDelay
+	}()
+
+	m := newFakeModule(t)
+	_, gameURL := newFakeGameServer(t)
+
+	var output syncBuffer
+	bots, err := m.RunBots(context.Background(), versions.MustParse("0.1.0"), BotsOptions{
+		GameURL: gameURL,
+		GameID:  "game",
+		Count:   1,
+		// the module starts a child process, which keeps stdout open
+		Args:   []string{"block"},
+		Output: &output,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removeSessions(t, bots)
+	for !strings.Contains(output.String(), "player player1") {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		bots.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Stop did not return")
+	}
+	if err := bots.Wait(); err != nil {
+		t.Errorf("Wait after Stop = %s, want nil", err)
+	}
+}
+
+// syncBuffer is a bytes.Buffer, which is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	a := &prefixWriter{prefix: []byte("[a] "), out: &out, mu: &mu}
+	b := &prefixWriter{prefix: []byte("[b] "), out: &out, mu: &mu}
+
+	a.Write([]byte("hel"))
+	b.Write([]byte("first\nsecond\n"))
+	a.Write([]byte("lo\nworld"))
+	a.flush()
+	b.flush()
+
+	want := "[b] first\n[b] second\n[a] hello\n[a] world\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestPrefixWriter_longLine(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := &prefixWriter{prefix: []byte("[a] "), out: &out, mu: &mu}
+
+	w.Write(bytes.Repeat([]byte("x"), maxBotLineLength+1))
+	if len(w.buf) != 1 {
+		t.Errorf("buffered %d bytes, want 1", len(w.buf))
+	}
+	w.flush()
+
+	want := "[a] " + strings.Repeat("x", maxBotLineLength) + "\n[a] x\n"
+	if out.String() != want {
+		t.Errorf("output has %d bytes, want %d", out.Len(), len(want))
+	}
+}
@@ -1,63 +1,131 @@
 package modules
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
 func GetCreateData() *ActionCreateData {
-	data, err := readActionData()
-	if err != nil {
-		panic(err)
-	}
 	message := &ActionCreateData{}
-	err = proto.Unmarshal(data, message)
-	if err != nil {
-		panic(err)
-	}
+	getActionData(message)
 	return message
 }
 
 func GetUpdateData() *ActionUpdateData {
-	data, err := readActionData()
-	if err != nil {
-		panic(err)
-	}
 	message := &ActionUpdateData{}
-	err = proto.Unmarshal(data, message)
-	if err != nil {
-		panic(err)
-	}
+	getActionData(message)
 	return message
 }
 
 func GetRunClientData() *ActionRunClientData {
+	message := &ActionRunClientData{}
+	getActionData(message)
+	return message
+}
+
+func GetRunServerData() *ActionRunServerData {
+	message := &ActionRunServerData{}
+	getActionData(message)
+	return message
+}
+
+func GetTestData() *ActionTestData {
+	message := &ActionTestData{}
+	getActionData(message)
+	return message
+}
+
+func getActionData(message proto.Message) {
 	data, err := readActionData()
 	if err != nil {
 		panic(err)
 	}
-	message := &ActionRunClientData{}
-	err = proto.Unmarshal(data, message)
+	err = decodeActionData(data, ActionDataFormat(os.Getenv("CG_MODULE_ACTION_DATA_FORMAT")), message)
 	if err != nil {
 		panic(err)
 	}
-	return message
+	warnNewerProtocol(message)
 }
 
-func GetRunServerData() *ActionRunServerData {
-	data, err := readActionData()
+// decodeActionData decodes data into message. If format is empty (older CLI versions), the format is detected from the data.
+func decodeActionData(data []byte, format ActionDataFormat, message proto.Message) error {
+	if format == "" {
+		format = ActionDataFormatProtobuf
+		if json.Valid(data) {
+			format = ActionDataFormatJSON
+		}
+	}
+	switch format {
+	case ActionDataFormatProtobuf:
+		return proto.Unmarshal(data, message)
+	case ActionDataFormatJSON:
+		return protojson.Unmarshal(data, message)
+	default:
+		return fmt.Errorf("unknown action data format '%s'", format)
+	}
+}
+
+// WriteActionResult sends result to the CLI. It should be called at most once per action.
+// Modules report errors by setting result.Error and planned changes in dry-run mode by setting result.PlannedChanges.
+func WriteActionResult(result *ActionResult) error {
+	path := os.Getenv("CG_MODULE_ACTION_RESULT_FILE")
+	if path == "" {
+		return errors.New("the CLI does not support action results")
+	}
+	data, err := encodeActionData(result, ActionDataFormat(os.Getenv("CG_MODULE_ACTION_DATA_FORMAT")))
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("encode action result: %w", err)
 	}
-	message := &ActionRunServerData{}
-	err = proto.Unmarshal(data, message)
+	err = os.WriteFile(path, data, 0o644)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("write action result file '%s': %w", path, err)
 	}
-	return message
+	return nil
+}
+
+// PlannedWrite returns a planned change, which writes content to path (relative to the project root).
+func PlannedWrite(path string, content []byte) *FileChange {
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+	return &FileChange{
+		Path:        filepath.ToSlash(path),
+		Kind:        FileChangeKind_WRITE,
+		ContentHash: &hashStr,
+	}
+}
+
+// PlannedDelete returns a planned change, which deletes path (relative to the project root).
+func PlannedDelete(path string) *FileChange {
+	return &FileChange{
+		Path: filepath.ToSlash(path),
+		Kind: FileChangeKind_DELETE,
+	}
+}
+
+// HasEffect returns false if applying the change to the project in projectRoot would not modify it.
+func (c *FileChange) HasEffect(projectRoot string) bool {
+	path := filepath.Join(projectRoot, filepath.FromSlash(c.Path))
+	if c.Kind == FileChangeKind_DELETE {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return true
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	return err != nil || hex.EncodeToString(hash.Sum(nil)) != c.GetContentHash()
 }
 
 func readActionData() ([]byte, error) {
@@ -0,0 +1,43 @@
+package modules
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_decodeActionData(t *testing.T) {
+	gameURL := "play.example.com"
+	want := &ActionCreateData{
+		ProjectType: ProjectType_CLIENT,
+		Language:    "go",
+		GameName:    "test",
+		GameURL:     &gameURL,
+	}
+	tests := []struct {
+		encoding ActionDataFormat
+		declared ActionDataFormat
+	}{
+		{ActionDataFormatProtobuf, ActionDataFormatProtobuf},
+		{ActionDataFormatJSON, ActionDataFormatJSON},
+		{ActionDataFormatProtobuf, ""},
+		{ActionDataFormatJSON, ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s as '%s'", tt.encoding, tt.declared), func(t *testing.T) {
+			data, err := encodeActionData(want, tt.encoding)
+			if err != nil {
+				t.Fatalf("encode: %s", err)
+			}
+			got := &ActionCreateData{}
+			err = decodeActionData(data, tt.declared, got)
+			if err != nil {
+				t.Fatalf("decode: %s", err)
+			}
+			if !proto.Equal(want, got) {
+				t.Errorf("decodeActionData = %v, want %v", got, want)
+			}
+		})
+	}
+}
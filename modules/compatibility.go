@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+var ErrNoCompatibleModuleVersion = errors.New("no compatible module version")
+
+// LanguageCompatibility describes whether a language can be used for a game with a specific CodeGame version.
+type LanguageCompatibility struct {
+	DisplayName string
+	// project type -> compatibility for all project types supported by the module
+	ProjectTypes map[string]ProjectCompatibility
+	// non-nil if the module could not be loaded
+	Err error
+}
+
+// Project returns the compatibility of projectType.
+func (c LanguageCompatibility) Project(projectType string) ProjectCompatibility {
+	if c.Err != nil {
+		return ProjectCompatibility{Reason: c.Err}
+	}
+	if p, ok := c.ProjectTypes[projectType]; ok {
+		return p
+	}
+	return ProjectCompatibility{Reason: ErrUnsupportedProjectType}
+}
+
+// ProjectCompatibility describes whether a project type of a language supports a specific CodeGame version.
+type ProjectCompatibility struct {
+	Compatible bool
+	// the versions which would be used (nil if not compatible)
+	LibraryVersion versions.Version
+	ModuleVersion  versions.Version
+	// why the project type is not compatible (nil if compatible)
+	Reason error
+}
+
+// CompatibleLanguages returns the compatibility of all languages in DefaultRegistry with cgVersion.
+func CompatibleLanguages(cgVersion versions.Version) map[string]LanguageCompatibility {
+	return DefaultRegistry.CompatibleLanguages(cgVersion)
+}
+
+// CompatibleLanguages returns the compatibility of all languages in the config file with cgVersion.
+// The modules are loaded concurrently.
+func (r *Registry) CompatibleLanguages(cgVersion versions.Version) map[string]LanguageCompatibility {
+	r.mu.Lock()
+	if r.rawModules == nil {
+		err := r.loadRawModules()
+		if err != nil {
+			r.mu.Unlock()
+			feedback.Error(FeedbackPkg, "Failed to load available languages: %s", err)
+			return map[string]LanguageCompatibility{}
+		}
+	}
+	rawModules := r.rawModules
+	r.mu.Unlock()
+
+	var mu sync.Mutex
+	result := make(map[string]LanguageCompatibility, len(rawModules))
+	var wg sync.WaitGroup
+	for lang, raw := range rawModules {
+		wg.Add(1)
+		go func(lang, displayName string) {
+			defer wg.Done()
+			compatibility := LanguageCompatibility{
+				DisplayName: displayName,
+			}
+			m, err := r.LoadModule(lang)
+			if err != nil {
+				compatibility.Err = err
+			} else {
+				compatibility.ProjectTypes = make(map[string]ProjectCompatibility)
+				for _, projectType := range m.ProjectTypes() {
+					compatibility.ProjectTypes[projectType] = m.projectCompatibility(projectType, cgVersion)
+				}
+			}
+			mu.Lock()
+			result[lang] = compatibility
+			mu.Unlock()
+		}(lang, raw.DisplayName)
+	}
+	wg.Wait()
+	return result
+}
+
+func (m *Module) projectCompatibility(projectType string, cgVersion versions.Version) ProjectCompatibility {
+	libVersion, err := m.findLibraryVersionByCGVersion(projectType, cgVersion)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedProjectType) {
+			return ProjectCompatibility{Reason: err}
+		}
+		return ProjectCompatibility{Reason: fmt.Errorf("%w: no library version supports CodeGame %s", ErrUnsupportedCodeGameVersion, cgVersion)}
+	}
+	modVersion, err := m.findCompatibleModuleVersion(projectType, libVersion)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedProjectType) || errors.Is(err, versions.ErrNoCompatibleVersion) {
+			err = fmt.Errorf("%w: no module version supports library version %s", ErrNoCompatibleModuleVersion, libVersion)
+		}
+		return ProjectCompatibility{
+			LibraryVersion: libVersion,
+			Reason:         err,
+		}
+	}
+	return ProjectCompatibility{
+		Compatible:     true,
+		LibraryVersion: libVersion,
+		ModuleVersion:  modVersion,
+	}
+}
+
+// CompatibleLanguageNames returns the sorted names of the languages in compatibility which support projectType.
+func CompatibleLanguageNames(compatibility map[string]LanguageCompatibility, projectType string) []string {
+	names := make([]string, 0, len(compatibility))
+	for name, c := range compatibility {
+		if c.Project(projectType).Compatible {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
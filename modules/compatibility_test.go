@@ -0,0 +1,57 @@
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func TestCompatibleLanguages(t *testing.T) {
+	registry := newTestRegistry(t, `{
+		"go": {
+			"display_name": "Go",
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.4"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}, "server": {"0.9": "0.3"}}
+		},
+		"js": {
+			"display_name": "JavaScript",
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "js-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.2"}},
+			"codegame_to_library_versions": {"client": {"0.8": "0.9"}}
+		},
+		"broken": {
+			"source": {"provider": "unknown"}
+		}
+	}`)
+
+	compatibility := registry.CompatibleLanguages(versions.MustParse("0.9"))
+	if len(compatibility) != 3 {
+		t.Fatalf("CompatibleLanguages returned %d languages, want 3", len(compatibility))
+	}
+
+	goLang := compatibility["go"]
+	if !goLang.Project(ProjectTypeClient).Compatible || goLang.Project(ProjectTypeClient).LibraryVersion.String() != "0.9" || goLang.Project(ProjectTypeClient).ModuleVersion.String() != "0.4" {
+		t.Errorf("go client = %+v, want library 0.9 and module 0.4", goLang.Project(ProjectTypeClient))
+	}
+	if goLang.Project(ProjectTypeServer).Compatible || !errors.Is(goLang.Project(ProjectTypeServer).Reason, ErrNoCompatibleModuleVersion) {
+		t.Errorf("go server = %+v, want ErrNoCompatibleModuleVersion", goLang.Project(ProjectTypeServer))
+	}
+
+	js := compatibility["js"]
+	if js.Project(ProjectTypeClient).Compatible || !errors.Is(js.Project(ProjectTypeClient).Reason, ErrUnsupportedCodeGameVersion) {
+		t.Errorf("js client = %+v, want ErrUnsupportedCodeGameVersion", js.Project(ProjectTypeClient))
+	}
+	if !errors.Is(js.Project(ProjectTypeServer).Reason, ErrUnsupportedProjectType) {
+		t.Errorf("js server = %+v, want ErrUnsupportedProjectType", js.Project(ProjectTypeServer))
+	}
+
+	if compatibility["broken"].Err == nil {
+		t.Error("broken language has no error")
+	}
+
+	if names := CompatibleLanguageNames(compatibility, ProjectTypeClient); len(names) != 1 || names[0] != "go" {
+		t.Errorf("CompatibleLanguageNames = %v, want [go]", names)
+	}
+}
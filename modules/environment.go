@@ -0,0 +1,121 @@
+package modules
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+// defaultAllowedEnv contains the environment variables, which are always passed to modules with a restricted environment.
+// A trailing '*' matches any suffix.
+var defaultAllowedEnv = []string{
+	"CG_*",
+	"PATH",
+	"HOME",
+	"USER",
+	"LANG",
+	"LC_*",
+	"TERM",
+	"TMPDIR",
+	"SHELL",
+	"XDG_*",
+	// Windows
+	"USERNAME",
+	"USERPROFILE",
+	"APPDATA",
+	"LOCALAPPDATA",
+	"TEMP",
+	"TMP",
+	"SYSTEMROOT",
+	"SYSTEMDRIVE",
+	"COMSPEC",
+	"PATHEXT",
+}
+
+// EnvironmentPolicy is configured with the 'environment' field of a module in lang_modules.json.
+type EnvironmentPolicy struct {
+	// Restricted modules only receive the environment variables in Allow and defaultAllowedEnv
+	// and are executed in the project root.
+	Restricted bool `json:"restricted"`
+	// A trailing '*' matches any suffix.
+	Allow  []string       `json:"allow,omitempty"`
+	Limits ResourceLimits `json:"limits,omitempty"`
+}
+
+// ResourceLimits are applied to module processes. Zero values are not limited.
+// Resource limits are currently only supported on Linux. On other platforms a warning is shown and modules run without limits.
+// They are best-effort: the limits are set right after the process has started, so a module may briefly run without them.
+// Child processes started by the module before that are not limited. If the limits cannot be set, the process is killed.
+type ResourceLimits struct {
+	CPUSeconds  uint64 `json:"cpu_seconds,omitempty"`
+	MemoryBytes uint64 `json:"memory_bytes,omitempty"`
+	OpenFiles   uint64 `json:"open_files,omitempty"`
+}
+
+func (l ResourceLimits) isZero() bool {
+	return l == ResourceLimits{}
+}
+
+// filterEnv returns the variables of env, which are allowed by the policy.
+func (p *EnvironmentPolicy) filterEnv(env []string) []string {
+	if p == nil || !p.Restricted {
+		return env
+	}
+
+	allowed := make([]string, 0, len(env))
+	var withheld []string
+	for _, e := range env {
+		name, _, _ := strings.Cut(e, "=")
+		if envAllowed(name, defaultAllowedEnv) || envAllowed(name, p.Allow) {
+			allowed = append(allowed, e)
+		} else {
+			withheld = append(withheld, name)
+		}
+	}
+	if len(withheld) > 0 {
+		sort.Strings(withheld)
+		feedback.Debug(FeedbackPkg, "Withheld environment variables from module: %s", strings.Join(withheld, ", "))
+	}
+	return allowed
+}
+
+// workingDir returns the directory a module with the policy is executed in if no other directory is specified.
+func (p *EnvironmentPolicy) workingDir() string {
+	if p == nil || !p.Restricted {
+		return ""
+	}
+	root, err := cgfile.FindProjectRoot()
+	if err != nil {
+		// e.g. create action
+		return ""
+	}
+	return root
+}
+
+func envAllowed(name string, allowList []string) bool {
+	if runtime.GOOS == "windows" {
+		name = strings.ToUpper(name)
+	}
+	for _, a := range allowList {
+		if runtime.GOOS == "windows" {
+			a = strings.ToUpper(a)
+		}
+		if prefix, ok := strings.CutSuffix(a, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == a {
+			return true
+		}
+	}
+	return false
+}
+
+// environ returns the environment of the CLI filtered by policy.
+func environ(policy *EnvironmentPolicy) []string {
+	return policy.filterEnv(os.Environ())
+}
@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestEnvironmentPolicy_filterEnv(t *testing.T) {
+	env := []string{"PATH=/usr/bin", "AWS_SECRET_ACCESS_KEY=secret", "CG_MODULE_ACTION_DATA_FILE=/tmp/data", "GOPATH=/go", "GOPROXY=direct", "XDG_CONFIG_HOME=/config"}
+	tests := []struct {
+		name   string
+		policy *EnvironmentPolicy
+		want   []string
+	}{
+		{"nil", nil, env},
+		{"unrestricted", &EnvironmentPolicy{Allow: []string{"GOPATH"}}, env},
+		{"restricted", &EnvironmentPolicy{Restricted: true}, []string{"PATH=/usr/bin", "CG_MODULE_ACTION_DATA_FILE=/tmp/data", "XDG_CONFIG_HOME=/config"}},
+		{"allow", &EnvironmentPolicy{Restricted: true, Allow: []string{"GO*"}}, []string{"PATH=/usr/bin", "CG_MODULE_ACTION_DATA_FILE=/tmp/data", "GOPATH=/go", "GOPROXY=direct", "XDG_CONFIG_HOME=/config"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.filterEnv(env)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterEnv = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_execInfo_environment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script module")
+	}
+	path := filepath.Join(t.TempDir(), "module")
+	script := `#!/bin/sh
+version=1.0.0
+[ -n "$AWS_SECRET_ACCESS_KEY" ] && version=2.0.0
+echo "{\"Version\": \"$version\", \"actions\": [], \"library_versions\": {}, \"project_types\": [], \"protocol_version\": 1}"
+`
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	tests := []struct {
+		name   string
+		policy *EnvironmentPolicy
+		want   string
+	}{
+		{"unrestricted", nil, "2.0.0"},
+		{"restricted", &EnvironmentPolicy{Restricted: true}, "1.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := execInfo(path, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Version.String() != tt.want {
+				t.Errorf("version = %s, want %s", info.Version, tt.want)
+			}
+		})
+	}
+}
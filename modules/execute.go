@@ -1,16 +1,31 @@
 package modules
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/code-game-project/cli-utils/versions"
 )
 
+var (
+	ErrActionFailed      = errors.New("module action failed")
+	ErrDryRunUnsupported = errors.New("the module does not support dry-runs")
+	ErrActionUnsupported = errors.New("the module does not support the action")
+)
+
+// processWaitDelay is the time to wait for the output pipes of a module process to be closed
+// after the process was killed or has exited.
+var processWaitDelay = 5 * time.Second
+
 type Action string
 
 const (
@@ -20,6 +35,15 @@ const (
 	ActionRunClient Action = "run_client"
 	ActionRunServer Action = "run_server"
 	ActionBuild     Action = "build"
+	ActionTest      Action = "test"
+)
+
+// ActionDataFormat is the encoding of the action data file passed to a module.
+type ActionDataFormat string
+
+const (
+	ActionDataFormatProtobuf ActionDataFormat = "protobuf"
+	ActionDataFormatJSON     ActionDataFormat = "json"
 )
 
 type ModuleInfo struct {
@@ -27,10 +51,18 @@ type ModuleInfo struct {
 	Actions         []Action                      `json:"actions"`
 	LibraryVersions map[string][]versions.Version `json:"library_versions"`
 	ProjectTypes    []string                      `json:"project_types"`
+	// empty -> protobuf
+	ActionDataFormat ActionDataFormat `json:"action_data_format,omitempty"`
+	// missing -> 0
+	ProtocolVersion uint32 `json:"protocol_version"`
 }
 
-func execInfo(modulePath string) (ModuleInfo, error) {
+// execInfo executes the info action of the module executable at modulePath with the environment and working directory
+// determined by policy (may be nil) and validates the response.
+func execInfo(modulePath string, policy *EnvironmentPolicy) (ModuleInfo, error) {
 	cmd := exec.Command(modulePath, string(ActionInfo))
+	cmd.Env = environ(policy)
+	cmd.Dir = policy.workingDir()
 	cmd.Stderr = os.Stderr
 	output, err := cmd.Output()
 	if err != nil {
@@ -50,80 +82,253 @@ func execInfo(modulePath string) (ModuleInfo, error) {
 	if resp.ProjectTypes == nil {
 		return ModuleInfo{}, fmt.Errorf("invalid info response: missing 'application_types' field")
 	}
+	switch resp.ActionDataFormat {
+	case "":
+		resp.ActionDataFormat = ActionDataFormatProtobuf
+	case ActionDataFormatProtobuf, ActionDataFormatJSON:
+	default:
+		return ModuleInfo{}, fmt.Errorf("invalid info response: unknown action data format '%s'", resp.ActionDataFormat)
+	}
+	err = checkModuleProtocolVersion(resp.ProtocolVersion)
+	if err != nil {
+		return ModuleInfo{}, err
+	}
 	return resp, nil
 }
 
 func (m *Module) ExecInfo(modVersion versions.Version) (ModuleInfo, error) {
-	path, err := m.install(modVersion)
+	resolution, err := m.Resolve(modVersion)
 	if err != nil {
-		return ModuleInfo{}, fmt.Errorf("install module: %w", err)
+		return ModuleInfo{}, err
 	}
-	return execInfo(path)
+	return m.info(resolution.Path)
 }
 
-func (m *Module) ExecCreateClient(gameName, gameURL, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
-	libraryVersion, err := m.findLibraryVersionByCGVersion(ProjectType_CLIENT, cgVersion)
+// info returns the cached info response of the module executable at path.
+func (m *Module) info(path string) (ModuleInfo, error) {
+	m.mu.Lock()
+	info, ok := m.infos[path]
+	m.mu.Unlock()
+	if ok {
+		return info, nil
+	}
+	info, err := execInfo(path, m.envPolicy)
 	if err != nil {
-		return nil, ErrUnsupportedCodeGameVersion
+		return ModuleInfo{}, err
 	}
-	modVersion, err = m.findCompatibleModuleVersion(ProjectType_CLIENT, libraryVersion)
+	m.mu.Lock()
+	m.infos[path] = info
+	m.mu.Unlock()
+	return info, nil
+}
+
+func (m *Module) ExecCreateClient(gameName, gameURL, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, data, err := m.createClientData(gameName, gameURL, language, cgVersion)
 	if err != nil {
 		return nil, err
 	}
+	return m.execute(modVersion, ProjectTypeClient, ActionCreate, data)
+}
+
+// PlanCreateClient returns the changes ExecCreateClient would apply without applying them.
+func (m *Module) PlanCreateClient(gameName, gameURL, language string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, data, err := m.createClientData(gameName, gameURL, language, cgVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	data.DryRun = true
+	return m.plan(modVersion, ProjectTypeClient, ActionCreate, data)
+}
+
+func (m *Module) createClientData(gameName, gameURL, language string, cgVersion versions.Version) (versions.Version, *ActionCreateData, error) {
+	libraryVersion, err := m.findLibraryVersionByCGVersion(ProjectTypeClient, cgVersion)
+	if err != nil {
+		return nil, nil, ErrUnsupportedCodeGameVersion
+	}
+	modVersion, err := m.findCompatibleModuleVersion(ProjectTypeClient, libraryVersion)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	libVersionStr := libraryVersion.String()
-	return modVersion, m.execute(modVersion, ProjectType_CLIENT, ActionCreate, &ActionCreateData{
+	return modVersion, &ActionCreateData{
 		Language:       language,
 		GameName:       gameName,
 		ProjectType:    ProjectType_CLIENT,
 		GameURL:        &gameURL,
 		LibraryVersion: &libVersionStr,
-	})
+	}, nil
 }
 
-func (m *Module) ExecCreateServer(gameName, language string) (modVersion versions.Version, err error) {
-	modVersion, err = m.findLatestModuleVersion(ProjectType_SERVER)
+// ExecCreateServer creates a server project using the module version compatible with cgVersion.
+// If cgVersion is nil, the latest module version is used.
+func (m *Module) ExecCreateServer(gameName, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(ProjectTypeServer, cgVersion)
 	if err != nil {
 		return nil, err
 	}
-	return modVersion, m.execute(modVersion, ProjectType_SERVER, ActionCreate, &ActionCreateData{
-		Language:    language,
-		GameName:    gameName,
-		ProjectType: ProjectType_SERVER,
-	})
+	return m.execute(modVersion, ProjectTypeServer, ActionCreate, projectCreateData(ProjectTypeServer, gameName, language, libraryVersion))
 }
 
-func (m *Module) ExecUpdateClient(language, gameURL string, cgVersion versions.Version) (modVersion versions.Version, err error) {
-	libraryVersion, err := m.findLibraryVersionByCGVersion(ProjectType_CLIENT, cgVersion)
+// PlanCreateServer returns the changes ExecCreateServer would apply without applying them.
+func (m *Module) PlanCreateServer(gameName, language string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(ProjectTypeServer, cgVersion)
 	if err != nil {
-		return nil, ErrUnsupportedCodeGameVersion
+		return nil, nil, err
 	}
-	modVersion, err = m.findCompatibleModuleVersion(ProjectType_CLIENT, libraryVersion)
+	data := projectCreateData(ProjectTypeServer, gameName, language, libraryVersion)
+	data.DryRun = true
+	return m.plan(modVersion, ProjectTypeServer, ActionCreate, data)
+}
+
+func (m *Module) ExecUpdateClient(language, gameURL string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, data, err := m.updateClientData(language, gameURL, cgVersion)
 	if err != nil {
 		return nil, err
 	}
+	return m.execute(modVersion, ProjectTypeClient, ActionUpdate, data)
+}
+
+// PlanUpdateClient returns the changes ExecUpdateClient would apply without applying them.
+func (m *Module) PlanUpdateClient(language, gameURL string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, data, err := m.updateClientData(language, gameURL, cgVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	data.DryRun = true
+	return m.plan(modVersion, ProjectTypeClient, ActionUpdate, data)
+}
+
+func (m *Module) updateClientData(language, gameURL string, cgVersion versions.Version) (versions.Version, *ActionUpdateData, error) {
+	libraryVersion, err := m.findLibraryVersionByCGVersion(ProjectTypeClient, cgVersion)
+	if err != nil {
+		return nil, nil, ErrUnsupportedCodeGameVersion
+	}
+	modVersion, err := m.findCompatibleModuleVersion(ProjectTypeClient, libraryVersion)
+	if err != nil {
+		return nil, nil, err
+	}
 	libVersionStr := libraryVersion.String()
-	return modVersion, m.execute(modVersion, ProjectType_CLIENT, ActionUpdate, &ActionUpdateData{
+	return modVersion, &ActionUpdateData{
 		ProjectType:    ProjectType_CLIENT,
 		Language:       language,
 		GameURL:        &gameURL,
 		LibraryVersion: &libVersionStr,
-	})
+	}, nil
 }
 
-func (m *Module) ExecUpdateServer(language, gameURL string, cgVersion versions.Version) (modVersion versions.Version, err error) {
-	modVersion, err = m.findLatestModuleVersion(ProjectType_CLIENT)
+// ExecUpdateServer updates a server project to the module version compatible with cgVersion.
+// If cgVersion is nil, the latest module version is used.
+func (m *Module) ExecUpdateServer(language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(ProjectTypeServer, cgVersion)
 	if err != nil {
-		return nil, ErrUnsupportedCodeGameVersion
+		return nil, err
 	}
-	return modVersion, m.execute(modVersion, ProjectType_CLIENT, ActionUpdate, &ActionUpdateData{
-		ProjectType: ProjectType_CLIENT,
-		Language:    language,
-	})
+	return m.execute(modVersion, ProjectTypeServer, ActionUpdate, projectUpdateData(ProjectTypeServer, language, libraryVersion))
+}
+
+// PlanUpdateServer returns the changes ExecUpdateServer would apply without applying them.
+func (m *Module) PlanUpdateServer(language string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(ProjectTypeServer, cgVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := projectUpdateData(ProjectTypeServer, language, libraryVersion)
+	data.DryRun = true
+	return m.plan(modVersion, ProjectTypeServer, ActionUpdate, data)
+}
+
+// ExecCreateProject creates a project of any projectType supported by the module (e.g. a project type declared in lang_modules.json
+// in addition to client and server) using the module version compatible with cgVersion.
+// If cgVersion is nil, the latest module version is used.
+func (m *Module) ExecCreateProject(projectType, gameName, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(projectType, cgVersion)
+	if err != nil {
+		return nil, err
+	}
+	return m.execute(modVersion, projectType, ActionCreate, projectCreateData(projectType, gameName, language, libraryVersion))
+}
+
+// PlanCreateProject returns the changes ExecCreateProject would apply without applying them.
+func (m *Module) PlanCreateProject(projectType, gameName, language string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(projectType, cgVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := projectCreateData(projectType, gameName, language, libraryVersion)
+	data.DryRun = true
+	return m.plan(modVersion, projectType, ActionCreate, data)
+}
+
+// ExecUpdateProject updates a project of any projectType supported by the module to the module version compatible with cgVersion.
+// If cgVersion is nil, the latest module version is used.
+func (m *Module) ExecUpdateProject(projectType, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(projectType, cgVersion)
+	if err != nil {
+		return nil, err
+	}
+	return m.execute(modVersion, projectType, ActionUpdate, projectUpdateData(projectType, language, libraryVersion))
+}
+
+// PlanUpdateProject returns the changes ExecUpdateProject would apply without applying them.
+func (m *Module) PlanUpdateProject(projectType, language string, cgVersion versions.Version) (modVersion versions.Version, changes []*FileChange, err error) {
+	modVersion, libraryVersion, err := m.projectVersions(projectType, cgVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := projectUpdateData(projectType, language, libraryVersion)
+	data.DryRun = true
+	return m.plan(modVersion, projectType, ActionUpdate, data)
+}
+
+// projectVersions resolves the module and library versions of projectType for cgVersion.
+// If cgVersion is nil, the latest module version and no library version are returned.
+func (m *Module) projectVersions(projectType string, cgVersion versions.Version) (modVersion, libraryVersion versions.Version, err error) {
+	if cgVersion == nil {
+		modVersion, err = m.findLatestModuleVersion(projectType)
+		return modVersion, nil, err
+	}
+	libraryVersion, err = m.findLibraryVersionByCGVersion(projectType, cgVersion)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedProjectType) {
+			return nil, nil, err
+		}
+		return nil, nil, ErrUnsupportedCodeGameVersion
+	}
+	modVersion, err = m.findCompatibleModuleVersion(projectType, libraryVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	return modVersion, libraryVersion, nil
+}
+
+func projectCreateData(projectType, gameName, language string, libraryVersion versions.Version) *ActionCreateData {
+	data := &ActionCreateData{
+		Language: language,
+		GameName: gameName,
+	}
+	data.ProjectType, data.ProjectTypeName = protoProjectType(projectType)
+	if libraryVersion != nil {
+		libVersionStr := libraryVersion.String()
+		data.LibraryVersion = &libVersionStr
+	}
+	return data
+}
+
+func projectUpdateData(projectType, language string, libraryVersion versions.Version) *ActionUpdateData {
+	data := &ActionUpdateData{
+		Language: language,
+	}
+	data.ProjectType, data.ProjectTypeName = protoProjectType(projectType)
+	if libraryVersion != nil {
+		libVersionStr := libraryVersion.String()
+		data.LibraryVersion = &libVersionStr
+	}
+	return data
 }
 
 func (m *Module) ExecRunClient(modVersion versions.Version, gameURL, language, gameID string, playerID, playerSecret *string, spectate bool, args []string) error {
-	return m.execute(modVersion, ProjectType_CLIENT, ActionRunClient, &ActionRunClientData{
+	_, err := m.execute(modVersion, ProjectTypeClient, ActionRunClient, &ActionRunClientData{
 		GameURL:      gameURL,
 		Language:     language,
 		Args:         args,
@@ -132,45 +337,284 @@ func (m *Module) ExecRunClient(modVersion versions.Version, gameURL, language, g
 		PlayerSecret: playerSecret,
 		Spectate:     spectate,
 	})
+	return err
 }
 
 func (m *Module) ExecRunServer(modVersion versions.Version, language string, port *int32, args []string) error {
-	return m.execute(modVersion, ProjectType_CLIENT, ActionRunServer, &ActionRunServerData{
+	_, err := m.execute(modVersion, ProjectTypeServer, ActionRunServer, &ActionRunServerData{
 		Language: language,
 		Args:     args,
 		Port:     port,
 	})
+	return err
 }
 
-func (m *Module) execute(modVersion versions.Version, projectType ProjectType, action Action, actionData proto.Message) error {
-	path, err := m.install(modVersion)
+// ExecTest runs the tests of the project in the current working directory.
+// The test results are also returned if the tests failed (err != nil). They are nil if the module did not report any.
+func (m *Module) ExecTest(modVersion versions.Version, projectType, language string, filter *string, verbosity TestVerbosity, args []string) (*TestResults, error) {
+	resolution, info, err := m.prepare(modVersion, ActionTest)
 	if err != nil {
-		return fmt.Errorf("install module: %w", err)
+		return nil, err
+	}
+	if !containsAction(info.Actions, ActionTest) {
+		return nil, fmt.Errorf("%w: %s", ErrActionUnsupported, ActionTest)
 	}
+	data := &ActionTestData{
+		Language:  language,
+		Filter:    filter,
+		Verbosity: verbosity,
+		Args:      args,
+	}
+	err = checkProjectType(info, projectType)
+	if err != nil {
+		return nil, err
+	}
+	data.ProjectType, data.ProjectTypeName = protoProjectType(projectType)
+	result, err := m.executePrepared(resolution.Path, info, ActionTest, data, ExecOptions{})
+	return result.GetTestResults(), err
+}
 
-	cmd := exec.Command(path, string(action))
-	cmd.Env = os.Environ()
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	var data []byte
+// execute executes action with actionData using the module executable for modVersion
+// and returns the version of the executable which was actually used (see prepare).
+func (m *Module) execute(modVersion versions.Version, projectType string, action Action, actionData proto.Message) (versions.Version, error) {
+	usedVersion, _, err := m.executeWithResult(modVersion, projectType, action, actionData)
+	return usedVersion, err
+}
+
+// plan executes a create or update action with actionData in dry-run mode.
+func (m *Module) plan(modVersion versions.Version, projectType string, action Action, actionData proto.Message) (versions.Version, []*FileChange, error) {
+	resolution, info, err := m.prepare(modVersion, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.ProtocolVersion < 2 {
+		return nil, nil, ErrDryRunUnsupported
+	}
+	err = checkProjectType(info, projectType)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := m.executePrepared(resolution.Path, info, action, actionData, ExecOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolution.Version, result.GetPlannedChanges(), nil
+}
+
+func (m *Module) executeWithResult(modVersion versions.Version, projectType string, action Action, actionData proto.Message) (versions.Version, *ActionResult, error) {
+	resolution, info, err := m.prepare(modVersion, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = checkProjectType(info, projectType)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := m.executePrepared(resolution.Path, info, action, actionData, ExecOptions{})
+	return resolution.Version, result, err
+}
+
+// prepare resolves the module executable for modVersion and receives its info.
+// Project overrides are ignored for the create action because there is no project yet.
+// The version of the returned resolution is the version of the executable which is used instead of modVersion
+// if it was overridden. It is never nil: executables overridden without a version report their version in their info.
+func (m *Module) prepare(modVersion versions.Version, action Action) (Resolution, ModuleInfo, error) {
+	resolution, err := m.resolve(modVersion, action != ActionCreate)
+	if err != nil {
+		return Resolution{}, ModuleInfo{}, err
+	}
+	info, err := m.info(resolution.Path)
+	if err != nil {
+		return Resolution{}, ModuleInfo{}, fmt.Errorf("receive module info: %w", err)
+	}
+	if resolution.Version == nil {
+		resolution.Version = info.Version
+	}
+	return resolution, info, nil
+}
+
+// executePrepared executes action using the module executable at path and records the execution.
+// The working directory and environment of options are determined by the environment policy of m.
+func (m *Module) executePrepared(path string, info ModuleInfo, action Action, actionData proto.Message, options ExecOptions) (*ActionResult, error) {
+	dir := m.envPolicy.workingDir()
+	record := ExecutionRecord{
+		Time:          time.Now(),
+		Lang:          m.Lang,
+		ModuleVersion: info.Version,
+		Action:        action,
+		ProjectDir:    dir,
+		ActionData:    redactActionData(actionData),
+	}
+	if record.ProjectDir == "" {
+		record.ProjectDir, _ = os.Getwd()
+	}
+
+	options.Dir = dir
+	options.Environment = m.envPolicy
+	options.Lang = m.Lang
+	result, err := ExecAction(path, info, action, actionData, options)
+
+	record.Duration = time.Since(record.Time)
+	record.ExitCode = exitCode(err)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	recordExecution(record)
+	return result, err
+}
+
+// ExecOptions configures the process of a module action.
+// Zero values default to the standard streams and working directory of the CLI.
+type ExecOptions struct {
+	// The process is killed when Context is done.
+	Context context.Context
+	// Start the process in a new process group and kill the entire group when Context is done.
+	// Processes in the group cannot read from the terminal. Only supported on Unix.
+	KillProcessGroup bool
+	Dir              string
+	Stdin            io.Reader
+	Stdout           io.Writer
+	Stderr           io.Writer
+	// nil -> pass the entire environment of the CLI
+	Environment *EnvironmentPolicy
+	// Answer prompts of the module without asking the user: inputs with their default value,
+	// yes/no questions with no and selections with the first option.
+	DeclinePrompts bool
+	// Progress reported by the module is namespaced as "module:<Lang>:<key>". Default: the file name of the module executable
+	Lang string
+}
+
+// ExecModuleInfo executes the info action of the module executable at modulePath with the entire environment of the CLI
+// and validates the response.
+func ExecModuleInfo(modulePath string) (ModuleInfo, error) {
+	return execInfo(modulePath, nil)
+}
+
+// ExecAction executes action with actionData (may be nil) using the module executable at modulePath.
+// info must be the info response of the same executable.
+// The returned result is nil if the module did not write one.
+// If the result contains an error, an error wrapping ErrActionFailed and the exit error of the process (if any) is returned.
+func ExecAction(modulePath string, info ModuleInfo, action Action, actionData proto.Message, options ExecOptions) (*ActionResult, error) {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, modulePath, string(action))
+	if options.Context != nil {
+		// don't wait indefinitely for child processes of the module, which inherited stdout or stderr
+		cmd.WaitDelay = processWaitDelay
+	}
+	if options.KillProcessGroup {
+		killProcessGroup(cmd)
+	}
+	cmd.Env = environ(options.Environment)
+	cmd.Dir = options.Dir
+	if cmd.Dir == "" {
+		cmd.Dir = options.Environment.workingDir()
+	}
+	cmd.Stdin = options.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = options.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = options.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
 	if actionData != nil {
-		data, err = proto.Marshal(actionData)
+		setProtocolVersion(actionData)
+		data, err := encodeActionData(actionData, info.ActionDataFormat)
 		if err != nil {
-			return fmt.Errorf("encode action data: %w", err)
+			return nil, fmt.Errorf("encode action data: %w", err)
 		}
 
 		file, err := os.CreateTemp(os.TempDir(), "codegame-module-action-data-*")
 		if err != nil {
-			return fmt.Errorf("create temporary file for action data: %w", err)
+			return nil, fmt.Errorf("create temporary file for action data: %w", err)
 		}
+		defer os.Remove(file.Name())
 
 		_, err = file.Write(data)
+		file.Close()
 		if err != nil {
-			return fmt.Errorf("write action data to temporary file: %w", err)
+			return nil, fmt.Errorf("write action data to temporary file: %w", err)
 		}
 
-		cmd.Env = append(cmd.Env, "CG_MODULE_ACTION_DATA_FILE="+file.Name())
+		cmd.Env = append(cmd.Env, "CG_MODULE_ACTION_DATA_FILE="+file.Name(), "CG_MODULE_ACTION_DATA_FORMAT="+string(info.ActionDataFormat))
+	}
+
+	resultFile, err := os.CreateTemp(os.TempDir(), "codegame-module-action-result-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary file for action result: %w", err)
+	}
+	resultFile.Close()
+	defer os.Remove(resultFile.Name())
+	cmd.Env = append(cmd.Env, "CG_MODULE_ACTION_RESULT_FILE="+resultFile.Name())
+
+	feedbackStarted, err := openFeedbackChannel(cmd, feedbackChannelOptions{
+		declinePrompts: options.DeclinePrompts,
+		progressPrefix: moduleProgressPrefix(modulePath, options.Lang),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	waitFeedback := feedbackStarted()
+	if err != nil {
+		waitFeedback()
+		return nil, err
+	}
+	// The limits can only be set after the process has started, so it runs without them until then (see ResourceLimits).
+	if options.Environment != nil && !options.Environment.Limits.isZero() {
+		err = applyResourceLimits(cmd.Process.Pid, options.Environment.Limits)
+		if err != nil {
+			// kills the process group with KillProcessGroup
+			cmd.Cancel()
+			cmd.Wait()
+			waitFeedback()
+			return nil, fmt.Errorf("apply resource limits: %w", err)
+		}
+	}
+	err = cmd.Wait()
+	waitFeedback()
+
+	result, resultErr := readActionResult(resultFile.Name())
+	if resultErr != nil {
+		return nil, errors.Join(err, resultErr)
+	}
+	if result.GetError() != "" {
+		if err != nil {
+			// keep the exit error for the exit code
+			return result, fmt.Errorf("%w: %s: %w", ErrActionFailed, result.GetError(), err)
+		}
+		return result, fmt.Errorf("%w: %s", ErrActionFailed, result.GetError())
+	}
+	return result, err
+}
+
+func readActionResult(path string) (*ActionResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read action result: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	result := &ActionResult{}
+	err = decodeActionData(data, "", result)
+	if err != nil {
+		return nil, fmt.Errorf("decode action result: %w", err)
+	}
+	return result, nil
+}
+
+func encodeActionData(actionData proto.Message, format ActionDataFormat) ([]byte, error) {
+	if format == ActionDataFormatJSON {
+		return protojson.Marshal(actionData)
 	}
-	return cmd.Run()
+	return proto.Marshal(actionData)
 }
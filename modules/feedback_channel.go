@@ -0,0 +1,343 @@
+package modules
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-game-project/cli-utils/cli"
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+// The feedback channel is a pair of pipes passed to module processes as extra file descriptors (not supported on Windows).
+// Modules write line-delimited JSON feedbackMessages to CG_MODULE_FEEDBACK_FD and read
+// feedbackResponses to prompts from CG_MODULE_FEEDBACK_RESPONSE_FD.
+
+const moduleFeedbackPkg = feedback.Package("module")
+
+type feedbackMessageType string
+
+const (
+	feedbackMessageLog      feedbackMessageType = "log"
+	feedbackMessageProgress feedbackMessageType = "progress"
+	feedbackMessagePrompt   feedbackMessageType = "prompt"
+)
+
+type promptKind string
+
+const (
+	promptInput  promptKind = "input"
+	promptYesNo  promptKind = "yes_no"
+	promptSelect promptKind = "select"
+)
+
+type feedbackMessage struct {
+	Type    feedbackMessageType `json:"type"`
+	Package feedback.Package    `json:"package,omitempty"`
+	Message string              `json:"message"`
+
+	// log
+	Severity string `json:"severity,omitempty"`
+
+	// progress
+	Process string `json:"process,omitempty"`
+	Current int64  `json:"current,omitempty"`
+	Total   int64  `json:"total,omitempty"`
+	Unit    string `json:"unit,omitempty"`
+
+	// prompt
+	Prompt   promptKind `json:"prompt,omitempty"`
+	Required bool       `json:"required,omitempty"`
+	Default  string     `json:"default,omitempty"`
+	Options  []string   `json:"options,omitempty"`
+}
+
+type feedbackResponse struct {
+	Text  string `json:"text,omitempty"`
+	Yes   bool   `json:"yes,omitempty"`
+	Index int    `json:"index,omitempty"`
+}
+
+// feedbackCloseTimeout is the time to wait for the remaining messages of a module after the module process exited.
+// Child processes of the module, which inherited the feedback channel, may keep it open.
+var feedbackCloseTimeout = 2 * time.Second
+
+// The prompt functions can be replaced in tests.
+var (
+	askInput  = cli.Input
+	askYesNo  = cli.YesNo
+	askSelect = cli.Select
+)
+
+var severityNames = map[feedback.Severity]string{
+	feedback.SeverityDebug: "debug",
+	feedback.SeverityInfo:  "info",
+	feedback.SeverityWarn:  "warn",
+	feedback.SeverityError: "error",
+	feedback.SeverityFatal: "fatal",
+}
+
+func parseSeverity(name string) feedback.Severity {
+	for s, n := range severityNames {
+		if n == name {
+			return s
+		}
+	}
+	return feedback.SeverityInfo
+}
+
+var unitNames = map[cli.Unit]string{
+	cli.UnitNone:     "none",
+	cli.UnitFileSize: "file_size",
+}
+
+func parseUnit(name string) cli.Unit {
+	if name == unitNames[cli.UnitFileSize] {
+		return cli.UnitFileSize
+	}
+	return cli.UnitNone
+}
+
+// feedbackChannelOptions configures how the messages of a module are handled.
+type feedbackChannelOptions struct {
+	// Answer prompts without asking the user: inputs with their default value, yes/no questions with no
+	// and selections with the first option.
+	declinePrompts bool
+	// prepended to the keys of progress messages, so that they don't collide with the keys of the CLI or other modules
+	progressPrefix string
+}
+
+// moduleProgressPrefix returns the progress key prefix of the module executable at modulePath for lang (may be empty).
+func moduleProgressPrefix(modulePath, lang string) string {
+	if lang == "" {
+		lang = strings.TrimSuffix(filepath.Base(modulePath), ".exe")
+	}
+	return "module:" + lang + ":"
+}
+
+// openFeedbackChannel passes the feedback channel to cmd. The returned function must be called after cmd was started.
+// It returns a function, which must be called after the process exited. It waits until the module closed the channel
+// or at most feedbackCloseTimeout.
+func openFeedbackChannel(cmd *exec.Cmd, options feedbackChannelOptions) (started func() (wait func()), err error) {
+	noop := func() (wait func()) { return func() {} }
+	if runtime.GOOS == "windows" {
+		return noop, nil
+	}
+
+	messagesR, messagesW, err := os.Pipe()
+	if err != nil {
+		return noop, fmt.Errorf("create feedback pipe: %w", err)
+	}
+	responsesR, responsesW, err := os.Pipe()
+	if err != nil {
+		messagesR.Close()
+		messagesW.Close()
+		return noop, fmt.Errorf("create feedback response pipe: %w", err)
+	}
+
+	fd := 3 + len(cmd.ExtraFiles)
+	cmd.ExtraFiles = append(cmd.ExtraFiles, messagesW, responsesR)
+	cmd.Env = append(cmd.Env, "CG_MODULE_FEEDBACK_FD="+strconv.Itoa(fd), "CG_MODULE_FEEDBACK_RESPONSE_FD="+strconv.Itoa(fd+1))
+
+	return func() (wait func()) {
+		// the module process holds its own copies
+		messagesW.Close()
+		responsesR.Close()
+
+		done := make(chan struct{})
+		go func() {
+			handleFeedbackChannel(messagesR, responsesW, options)
+			messagesR.Close()
+			responsesW.Close()
+			close(done)
+		}()
+		return func() {
+			messagesR.SetReadDeadline(time.Now().Add(feedbackCloseTimeout))
+			<-done
+		}
+	}, nil
+}
+
+// handleFeedbackChannel forwards all messages in messages to the feedback package and writes the responses to prompts to responses.
+func handleFeedbackChannel(messages io.Reader, responses io.Writer, options feedbackChannelOptions) {
+	scanner := bufio.NewScanner(messages)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(responses)
+	for scanner.Scan() {
+		var msg feedbackMessage
+		err := json.Unmarshal(scanner.Bytes(), &msg)
+		if err != nil {
+			feedback.Debug(FeedbackPkg, "Invalid feedback message from module: %s", err)
+			continue
+		}
+		pkg := msg.Package
+		if pkg == "" {
+			pkg = moduleFeedbackPkg
+		}
+		switch msg.Type {
+		case feedbackMessageLog:
+			feedback.Log(pkg, parseSeverity(msg.Severity), "%s", msg.Message)
+		case feedbackMessageProgress:
+			feedback.Progress(pkg, options.progressPrefix+msg.Process, msg.Message, msg.Current, msg.Total, parseUnit(msg.Unit))
+		case feedbackMessagePrompt:
+			var resp feedbackResponse
+			if options.declinePrompts {
+				feedback.Debug(FeedbackPkg, "Declined prompt from module: %s", msg.Message)
+				if msg.Prompt == promptInput {
+					resp.Text = msg.Default
+				}
+				err = encoder.Encode(resp)
+				if err != nil {
+					feedback.Debug(FeedbackPkg, "Failed to send prompt response to module: %s", err)
+				}
+				continue
+			}
+			switch msg.Prompt {
+			case promptInput:
+				resp.Text = askInput(msg.Message, msg.Required, msg.Default)
+			case promptYesNo:
+				resp.Yes = askYesNo(msg.Message, msg.Default == "true")
+			case promptSelect:
+				resp.Index = askSelect(msg.Message, msg.Options)
+			default:
+				feedback.Debug(FeedbackPkg, "Unknown prompt kind from module: %s", msg.Prompt)
+			}
+			err = encoder.Encode(resp)
+			if err != nil {
+				feedback.Debug(FeedbackPkg, "Failed to send prompt response to module: %s", err)
+			}
+		default:
+			feedback.Debug(FeedbackPkg, "Unknown feedback message type from module: %s", msg.Type)
+		}
+	}
+}
+
+var ErrNoFeedbackChannel = errors.New("no feedback channel")
+
+// ModuleFeedback is used by modules to send feedback to the CLI.
+// It implements feedback.FeedbackReceiver, so that it can be passed to feedback.Enable.
+type ModuleFeedback struct {
+	mu        sync.Mutex
+	messages  io.Writer
+	responses *bufio.Reader
+}
+
+// NewModuleFeedback opens the feedback channel provided by the CLI.
+// It returns ErrNoFeedbackChannel if the CLI does not provide one (e.g. older CLI versions or on Windows).
+func NewModuleFeedback() (*ModuleFeedback, error) {
+	messagesFD, err := strconv.Atoi(os.Getenv("CG_MODULE_FEEDBACK_FD"))
+	if err != nil {
+		return nil, ErrNoFeedbackChannel
+	}
+	responsesFD, err := strconv.Atoi(os.Getenv("CG_MODULE_FEEDBACK_RESPONSE_FD"))
+	if err != nil {
+		return nil, ErrNoFeedbackChannel
+	}
+	return newModuleFeedback(os.NewFile(uintptr(messagesFD), "feedback"), os.NewFile(uintptr(responsesFD), "feedback-responses")), nil
+}
+
+func newModuleFeedback(messages io.Writer, responses io.Reader) *ModuleFeedback {
+	return &ModuleFeedback{
+		messages:  messages,
+		responses: bufio.NewReader(responses),
+	}
+}
+
+func (f *ModuleFeedback) Log(pkg feedback.Package, severity feedback.Severity, message string) {
+	f.send(feedbackMessage{
+		Type:     feedbackMessageLog,
+		Package:  pkg,
+		Severity: severityNames[severity],
+		Message:  message,
+	})
+}
+
+func (f *ModuleFeedback) Progress(pkg feedback.Package, process, message string, current, total int64, unit cli.Unit) {
+	f.send(feedbackMessage{
+		Type:    feedbackMessageProgress,
+		Package: pkg,
+		Process: process,
+		Message: message,
+		Current: current,
+		Total:   total,
+		Unit:    unitNames[unit],
+	})
+}
+
+// Input asks the user of the CLI to input a line of text.
+func (f *ModuleFeedback) Input(prompt string, required bool, defaultValue string) (string, error) {
+	resp, err := f.prompt(feedbackMessage{
+		Prompt:   promptInput,
+		Message:  prompt,
+		Required: required,
+		Default:  defaultValue,
+	})
+	return resp.Text, err
+}
+
+// YesNo asks the user of the CLI a yes/no question.
+func (f *ModuleFeedback) YesNo(question string, defaultValue bool) (bool, error) {
+	resp, err := f.prompt(feedbackMessage{
+		Prompt:  promptYesNo,
+		Message: question,
+		Default: strconv.FormatBool(defaultValue),
+	})
+	return resp.Yes, err
+}
+
+// Select asks the user of the CLI to select an option. It returns the index of the chosen option.
+func (f *ModuleFeedback) Select(msg string, options []string) (int, error) {
+	resp, err := f.prompt(feedbackMessage{
+		Prompt:  promptSelect,
+		Message: msg,
+		Options: options,
+	})
+	return resp.Index, err
+}
+
+func (f *ModuleFeedback) prompt(msg feedbackMessage) (feedbackResponse, error) {
+	msg.Type = feedbackMessagePrompt
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.write(msg)
+	if err != nil {
+		return feedbackResponse{}, fmt.Errorf("send prompt: %w", err)
+	}
+	line, err := f.responses.ReadBytes('\n')
+	if err != nil {
+		return feedbackResponse{}, fmt.Errorf("receive prompt response: %w", err)
+	}
+	var resp feedbackResponse
+	err = json.Unmarshal(line, &resp)
+	if err != nil {
+		return feedbackResponse{}, fmt.Errorf("decode prompt response: %w", err)
+	}
+	return resp, nil
+}
+
+func (f *ModuleFeedback) send(msg feedbackMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.write(msg)
+}
+
+// write must be called with f.mu locked.
+func (f *ModuleFeedback) write(msg feedbackMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = f.messages.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,159 @@
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/code-game-project/cli-utils/cli"
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+type recordingReceiver struct {
+	entries []string
+}
+
+func (r *recordingReceiver) Log(pkg feedback.Package, severity feedback.Severity, message string) {
+	if severity == feedback.SeverityDebug {
+		return
+	}
+	r.entries = append(r.entries, fmt.Sprintf("log %s %d %s", pkg, severity, message))
+}
+
+func (r *recordingReceiver) Progress(pkg feedback.Package, process, message string, current, total int64, unit cli.Unit) {
+	r.entries = append(r.entries, fmt.Sprintf("progress %s %s %s %d/%d %d", pkg, process, message, current, total, unit))
+}
+
+func Test_handleFeedbackChannel(t *testing.T) {
+	var messages bytes.Buffer
+	module := newModuleFeedback(&messages, strings.NewReader(""))
+	module.Log("go-module", feedback.SeverityWarn, "outdated library")
+	module.Progress("go-module", "download", "Downloading library", 512, 1024, cli.UnitFileSize)
+	messages.WriteString("invalid\n")
+
+	receiver := &recordingReceiver{}
+	feedback.Enable(receiver)
+	defer feedback.Disable()
+
+	var responses bytes.Buffer
+	handleFeedbackChannel(&messages, &responses, feedbackChannelOptions{progressPrefix: moduleProgressPrefix("/bin/go-module", "go")})
+
+	want := []string{
+		fmt.Sprintf("log go-module %d outdated library", feedback.SeverityWarn),
+		fmt.Sprintf("progress go-module module:go:download Downloading library 512/1024 %d", cli.UnitFileSize),
+	}
+	if !reflect.DeepEqual(receiver.entries, want) {
+		t.Errorf("received = %v, want %v", receiver.entries, want)
+	}
+	if responses.Len() > 0 {
+		t.Errorf("unexpected responses: %s", responses.String())
+	}
+}
+
+func Test_moduleProgressPrefix(t *testing.T) {
+	tests := []struct {
+		modulePath, lang, want string
+	}{
+		{"/bin/go-module", "go", "module:go:"},
+		{"/bin/go-module", "", "module:go-module:"},
+		{"C:/bin/go-module.exe", "", "module:go-module:"},
+	}
+	for _, tt := range tests {
+		if got := moduleProgressPrefix(tt.modulePath, tt.lang); got != tt.want {
+			t.Errorf("moduleProgressPrefix(%q, %q) = %q, want %q", tt.modulePath, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func Test_handleFeedbackChannel_prompts(t *testing.T) {
+	oldInput, oldYesNo, oldSelect := askInput, askYesNo, askSelect
+	defer func() {
+		askInput, askYesNo, askSelect = oldInput, oldYesNo, oldSelect
+	}()
+	var prompts []string
+	askInput = func(prompt string, required bool, defaultValue string, validators ...cli.Validator) string {
+		prompts = append(prompts, fmt.Sprintf("input %s %t %s", prompt, required, defaultValue))
+		return "my-game"
+	}
+	askYesNo = func(question string, defaultValue bool) bool {
+		prompts = append(prompts, fmt.Sprintf("yes_no %s %t", question, defaultValue))
+		return true
+	}
+	askSelect = func(msg string, options []string) int {
+		prompts = append(prompts, fmt.Sprintf("select %s %s", msg, strings.Join(options, ",")))
+		return 1
+	}
+
+	messagesR, messagesW := io.Pipe()
+	responsesR, responsesW := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		handleFeedbackChannel(messagesR, responsesW, feedbackChannelOptions{})
+		close(done)
+	}()
+
+	module := newModuleFeedback(messagesW, responsesR)
+	text, err := module.Input("Name", true, "game")
+	if err != nil || text != "my-game" {
+		t.Errorf("Input = %q, %v, want my-game", text, err)
+	}
+	yes, err := module.YesNo("Continue?", false)
+	if err != nil || !yes {
+		t.Errorf("YesNo = %t, %v, want true", yes, err)
+	}
+	index, err := module.Select("Language", []string{"go", "js"})
+	if err != nil || index != 1 {
+		t.Errorf("Select = %d, %v, want 1", index, err)
+	}
+	messagesW.Close()
+	<-done
+
+	want := []string{"input Name true game", "yes_no Continue? false", "select Language go,js"}
+	if !reflect.DeepEqual(prompts, want) {
+		t.Errorf("prompts = %v, want %v", prompts, want)
+	}
+}
+
+func Test_openFeedbackChannel_inherited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no feedback channel on Windows")
+	}
+	oldTimeout := feedbackCloseTimeout
+	feedbackCloseTimeout = 100 * time.Millisecond
+	defer func() {
+		feedbackCloseTimeout = oldTimeout
+	}()
+
+	// the background process inherits the feedback channel and keeps it open after the shell exited
+	cmd := exec.Command("sh", "-c", "sleep 2 &")
+	cmd.Env = os.Environ()
+	started, err := openFeedbackChannel(cmd, feedbackChannelOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cmd.Start()
+	wait := started()
+	if err != nil {
+		wait()
+		t.Fatal(err)
+	}
+	cmd.Wait()
+
+	waited := make(chan struct{})
+	go func() {
+		wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("waiting for the feedback channel did not time out")
+	}
+}
@@ -0,0 +1,218 @@
+package modules
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type ignoreRule struct {
+	base    string // slash separated directory of the .gitignore file relative to the project root
+	regexp  *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreMatcher implements the subset of the .gitignore format used by the projects created by modules.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// load adds the rules of the .gitignore file in dir (relative to ignoreRoot) to the matcher.
+func (i *ignoreMatcher) load(ignoreRoot, dir string) error {
+	file, err := os.Open(filepath.Join(ignoreRoot, dir, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	base := filepath.ToSlash(dir)
+	if base == "." {
+		base = ""
+	}
+	return i.parse(base, file)
+}
+
+// parse adds the rules in r, which apply to the files in the slash separated directory base, to the matcher.
+func (i *ignoreMatcher) parse(base string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := trimTrailingSpaces(strings.TrimSuffix(scanner.Text(), "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		expr := globToRegexp(line)
+		if anchored {
+			expr = "^" + expr + "$"
+		} else {
+			expr = "(^|/)" + expr + "$"
+		}
+		var err error
+		rule.regexp, err = regexp.Compile(expr)
+		if err != nil {
+			// ignore invalid patterns like git does
+			continue
+		}
+		i.rules = append(i.rules, rule)
+	}
+	return scanner.Err()
+}
+
+// trimTrailingSpaces removes trailing spaces, which are not escaped with a backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// ignored reports whether the slash separated path relative to the project root is ignored.
+func (i *ignoreMatcher) ignored(relPath string, isDir bool) bool {
+	ignored := false
+	for _, r := range i.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		p := relPath
+		if r.base != "" {
+			if !strings.HasPrefix(p, r.base+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(p, r.base+"/")
+		}
+		if r.regexp.MatchString(p) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegexp converts a gitignore pattern without leading and trailing slashes to a regular expression.
+func globToRegexp(pattern string) string {
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**") && (i == 0 || pattern[i-1] == '/') &&
+			(i+2 == len(pattern) || pattern[i+2] == '/'):
+			// "**" only matches across directories if it is a complete path segment
+			if i+2 == len(pattern) {
+				expr.WriteString(".*")
+			} else {
+				expr.WriteString("(.*/)?")
+			}
+			i += 2
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			class, n := globClass(pattern[i+1:])
+			if n < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			expr.WriteString(class)
+			i += n
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
+
+// globClass converts the bracket expression at the start of pattern (after the opening bracket) to a character class
+// of a regular expression. It returns the number of bytes consumed including the closing bracket or -1 if the bracket
+// expression is not closed.
+func globClass(pattern string) (string, int) {
+	var class strings.Builder
+	class.WriteString("[")
+	i := 0
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		class.WriteString("^")
+		i++
+	}
+	// a closing bracket at the start is part of the class
+	if i < len(pattern) && pattern[i] == ']' {
+		class.WriteString(`\]`)
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == ']':
+			class.WriteString("]")
+			return class.String(), i + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			class.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case c == '[' || c == '^':
+			class.WriteString(`\` + string(c))
+		default:
+			class.WriteByte(c)
+		}
+	}
+	return "", -1
+}
+
+// trackedFiles returns the slash separated paths of all files, directories and symbolic links in root which are not
+// ignored by .gitignore files. The .gitignore files are read from ignoreRoot, which has the same layout as root.
+// Symbolic links are not followed.
+func trackedFiles(root, ignoreRoot string) (files, dirs, symlinks []string, err error) {
+	var matcher ignoreMatcher
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		err := matcher.load(ignoreRoot, dir)
+		if err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			rel := path.Join(filepath.ToSlash(dir), e.Name())
+			if e.Name() == ".git" || matcher.ignored(rel, e.IsDir()) {
+				continue
+			}
+			switch {
+			case e.IsDir():
+				dirs = append(dirs, rel)
+				err = walk(rel)
+				if err != nil {
+					return err
+				}
+			case e.Type()&os.ModeSymlink != 0:
+				symlinks = append(symlinks, rel)
+			case e.Type().IsRegular():
+				files = append(files, rel)
+			}
+		}
+		return nil
+	}
+	err = walk(".")
+	return files, dirs, symlinks, err
+}
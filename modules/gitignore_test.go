@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ignoreMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"extension", "*.log", "a/b.log", false, true},
+		{"extension suffix", "*.log", "a.logx", false, false},
+		{"anchored", "/build", "build", true, true},
+		{"anchored subdirectory", "/build", "src/build", true, false},
+		{"middle slash", "doc/*.txt", "doc/a.txt", false, true},
+		{"middle slash nested", "doc/*.txt", "doc/x/a.txt", false, false},
+		{"leading double star", "**/foo", "a/b/foo", false, true},
+		{"leading double star root", "**/foo", "foo", false, true},
+		{"middle double star", "a/**/b", "a/x/y/b", false, true},
+		{"middle double star direct", "a/**/b", "a/b", false, true},
+		{"trailing double star", "a/**", "a/x/y", false, true},
+		{"trailing double star dir", "a/**", "a", true, false},
+		{"double star in name", "a**b", "axxb", false, true},
+		{"double star in name across dirs", "a**b", "ax/yb", false, false},
+		{"escaped exclamation mark", `\!important`, "!important", false, true},
+		{"escaped hash", `\#file`, "#file", false, true},
+		{"escaped star", `\*`, "*", false, true},
+		{"escaped star literal", `\*`, "x", false, false},
+		{"escaped trailing space", `trailing\ `, "trailing ", false, true},
+		{"trailing spaces", "space   ", "space", false, true},
+		{"comment", "#file", "#file", false, false},
+		{"negation", "*.log\n!keep.log", "keep.log", false, false},
+		{"negation order", "!keep.log\n*.log", "keep.log", false, true},
+		{"directory only", "build/", "build", true, true},
+		{"directory only file", "build/", "build", false, false},
+		{"class", "[a-c]x", "bx", false, true},
+		{"negated class", "[!a]b", "ab", false, false},
+		{"negated class match", "[!a]b", "cb", false, true},
+		{"class with bracket", "[]a]x", "]x", false, true},
+		{"unclosed class", "[ab", "[ab", false, true},
+		{"question mark", "?.go", "a/b.go", false, true},
+		{"question mark slash", "a?b", "a/b", false, false},
+		{"carriage return", "*.tmp\r", "a.tmp", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matcher ignoreMatcher
+			err := matcher.parse("", strings.NewReader(tt.rules))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := matcher.ignored(tt.path, tt.isDir); got != tt.ignored {
+				t.Errorf("ignored(%q) with %q = %t, want %t", tt.path, tt.rules, got, tt.ignored)
+			}
+		})
+	}
+}
+
+func Test_ignoreMatcher_base(t *testing.T) {
+	var matcher ignoreMatcher
+	err := matcher.parse("src", strings.NewReader("/gen\n*.o\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"src/gen":     true,
+		"gen":         false,
+		"src/sub/gen": false,
+		"src/sub/a.o": true,
+		"a.o":         false,
+	}
+	for path, want := range tests {
+		if got := matcher.ignored(path, false); got != want {
+			t.Errorf("ignored(%q) = %t, want %t", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+var historyPath = filepath.Join(xdg.DataHome, "codegame", "module_history.jsonl")
+
+const (
+	// The history file is truncated to maxHistoryEntries when it grows larger than maxHistorySize.
+	maxHistoryEntries = 500
+	maxHistorySize    = 1 << 20
+)
+
+var historyMu sync.Mutex
+
+// ExecutionRecord describes a module execution in the history file.
+type ExecutionRecord struct {
+	Time          time.Time        `json:"time"`
+	Lang          string           `json:"lang"`
+	ModuleVersion versions.Version `json:"module_version,omitempty"`
+	Action        Action           `json:"action"`
+	ProjectDir    string           `json:"project_dir"`
+	Duration      time.Duration    `json:"duration"`
+	// -1 if the process could not be started or was terminated by a signal
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+	// secrets are redacted
+	ActionData json.RawMessage `json:"action_data,omitempty"`
+}
+
+// RecentExecutions returns up to limit module executions from the history file (newest first).
+// If limit <= 0, all executions are returned.
+// If lang is not empty, only executions of the module for lang are returned.
+func RecentExecutions(limit int, lang string) ([]ExecutionRecord, error) {
+	historyMu.Lock()
+	data, err := os.ReadFile(historyPath)
+	historyMu.Unlock()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make([]ExecutionRecord, 0), nil
+		}
+		return nil, fmt.Errorf("read module history: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	if limit <= 0 || limit > len(lines) {
+		limit = len(lines)
+	}
+	records := make([]ExecutionRecord, 0, limit)
+	for i := len(lines) - 1; i >= 0 && len(records) < limit; i-- {
+		var record ExecutionRecord
+		err = json.Unmarshal(lines[i], &record)
+		if err != nil {
+			continue
+		}
+		if lang == "" || record.Lang == lang {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func recordExecution(record ExecutionRecord) {
+	err := appendHistory(record)
+	if err != nil {
+		feedback.Debug(FeedbackPkg, "Failed to record module execution: %s", err)
+	}
+}
+
+func appendHistory(record ExecutionRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	err = os.MkdirAll(filepath.Dir(historyPath), 0o755)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	if stat, err := os.Stat(historyPath); err == nil && stat.Size() > maxHistorySize {
+		return truncateHistory()
+	}
+	return nil
+}
+
+// truncateHistory must be called with historyMu locked.
+func truncateHistory() error {
+	file, err := os.Open(historyPath)
+	if err != nil {
+		return err
+	}
+	lines := make([]string, 0, maxHistoryEntries)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > maxHistoryEntries {
+			lines = lines[1:]
+		}
+	}
+	file.Close()
+	if scanner.Err() != nil {
+		return scanner.Err()
+	}
+	return os.WriteFile(historyPath, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+}
+
+// exitCode returns the exit code of a process, which returned err.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// redactActionData encodes actionData as JSON with secrets replaced.
+// The values of all string fields (also in nested messages) with names containing 'secret', 'token' or 'password' are redacted.
+// In string lists like program arguments, the values of flags with such names are redacted
+// (e.g. '--token=abc' or '--token abc').
+func redactActionData(actionData proto.Message) json.RawMessage {
+	if actionData == nil {
+		return nil
+	}
+	message := proto.Clone(actionData).ProtoReflect()
+	redactMessage(message)
+	data, err := protojson.Marshal(message.Interface())
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redactMessage(message protoreflect.Message) {
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsMap():
+		case field.Kind() == protoreflect.MessageKind && field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case field.Kind() == protoreflect.MessageKind:
+			redactMessage(value.Message())
+		case field.Kind() == protoreflect.StringKind && field.IsList():
+			redactArgs(value.List())
+		case field.Kind() == protoreflect.StringKind && isSecretName(string(field.Name())):
+			message.Set(field, protoreflect.ValueOfString("REDACTED"))
+		}
+		return true
+	})
+}
+
+// redactArgs redacts the values of flags with secret names in args.
+func redactArgs(args protoreflect.List) {
+	for i := 0; i < args.Len(); i++ {
+		arg := args.Get(i).String()
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flag, _, hasValue := strings.Cut(arg, "=")
+		if !isSecretName(flag) {
+			continue
+		}
+		if hasValue {
+			args.Set(i, protoreflect.ValueOfString(flag+"=REDACTED"))
+		} else if i+1 < args.Len() {
+			args.Set(i+1, protoreflect.ValueOfString("REDACTED"))
+			i++
+		}
+	}
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "secret") || strings.Contains(name, "token") || strings.Contains(name, "password")
+}
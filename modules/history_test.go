@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecentExecutions(t *testing.T) {
+	oldHistoryPath := historyPath
+	historyPath = filepath.Join(t.TempDir(), "module_history.jsonl")
+	defer func() {
+		historyPath = oldHistoryPath
+	}()
+
+	secret := "top-secret"
+	for i, lang := range []string{"go", "js", "go"} {
+		recordExecution(ExecutionRecord{
+			Time:     time.Unix(int64(i), 0),
+			Lang:     lang,
+			Action:   ActionRunClient,
+			ExitCode: i,
+			ActionData: redactActionData(&ActionRunClientData{
+				GameID:       "game",
+				PlayerSecret: &secret,
+			}),
+		})
+	}
+
+	records, err := RecentExecutions(10, "go")
+	if err != nil {
+		t.Fatalf("RecentExecutions: %s", err)
+	}
+	if len(records) != 2 || records[0].ExitCode != 2 || records[1].ExitCode != 0 {
+		t.Fatalf("RecentExecutions = %v, want the 2 go executions (newest first)", records)
+	}
+	if strings.Contains(string(records[0].ActionData), secret) || !strings.Contains(string(records[0].ActionData), "game") {
+		t.Errorf("action data = %s, want redacted player secret", records[0].ActionData)
+	}
+
+	records, err = RecentExecutions(-1, "")
+	if err != nil {
+		t.Fatalf("RecentExecutions: %s", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("RecentExecutions(-1) = %d executions, want all 3", len(records))
+	}
+
+	records, err = RecentExecutions(1, "")
+	if err != nil {
+		t.Fatalf("RecentExecutions: %s", err)
+	}
+	if len(records) != 1 || records[0].Lang != "go" {
+		t.Errorf("RecentExecutions(1) = %v, want the latest execution", records)
+	}
+}
+
+func Test_redactActionData(t *testing.T) {
+	data := string(redactActionData(&ActionRunClientData{
+		GameID: "game",
+		Args:   []string{"--name", "bot", "--api-token=abc", "--password", "def", "-v"},
+	}))
+	for _, secret := range []string{"abc", "def"} {
+		if strings.Contains(data, secret) {
+			t.Errorf("action data = %s, want %s redacted", data, secret)
+		}
+	}
+	for _, arg := range []string{`"--api-token=REDACTED"`, `"--password"`, `"bot"`, `"-v"`} {
+		if !strings.Contains(data, arg) {
+			t.Errorf("action data = %s, want argument %s", data, arg)
+		}
+	}
+
+	data = string(redactActionData(&ActionResult{
+		PlannedChanges: []*FileChange{{Path: "token.txt"}},
+		TestResults:    &TestResults{FailedTests: []string{"--token"}},
+	}))
+	if !strings.Contains(data, `"token.txt"`) || !strings.Contains(data, `"--token"`) {
+		t.Errorf("action data = %s, want nested non-secret fields unchanged", data)
+	}
+}
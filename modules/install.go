@@ -1,74 +1,121 @@
 package modules
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
 
+	"github.com/code-game-project/cli-utils/cli"
+	"github.com/code-game-project/cli-utils/feedback"
 	"github.com/code-game-project/cli-utils/versions"
 )
 
 var moduleBinPath = filepath.Join(xdg.DataHome, "codegame", "modules")
 
 func (m *Module) install(moduleVersion versions.Version) (string, error) {
+	binPath, _, err := m.installVersion(moduleVersion, false, nil)
+	return binPath, err
+}
+
+// installVersion is like install but also reports whether the module binary had to be downloaded.
+// If force is true, the module binary is downloaded even if it is already installed.
+// The download progress is reported to progress or, if it is nil, to feedback.
+func (m *Module) installVersion(moduleVersion versions.Version, force bool, progress downloadProgressFunc) (binPath string, downloaded bool, err error) {
 	dirName := filepath.Join(moduleBinPath, m.Lang)
-	err := os.MkdirAll(dirName, 0o755)
+	err = os.MkdirAll(dirName, 0o755)
 	if err != nil {
-		return "", fmt.Errorf("create module binary directory: %w", err)
+		return "", false, fmt.Errorf("create module binary directory: %w", err)
 	}
 
-	version, err := m.provider.FindExactVersion(m.providerVars, moduleVersion)
+	version, err := m.source.FindExactVersion(moduleVersion)
 	if err != nil {
-		return "", fmt.Errorf("determine exact module version: %w", err)
+		return "", false, fmt.Errorf("determine exact module version: %w", err)
 	}
 
-	var binPath string
-	if p, ok := m.installedExecutables[version.String()]; ok {
-		binPath = p
-	} else if m.provider.Name() != "local" {
-		tempBinPath := filepath.Join(dirName, strings.ReplaceAll(moduleVersion.String(), ".", "-")+".temp")
-		file, err := os.OpenFile(tempBinPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o755)
-		if err != nil {
-			return "", fmt.Errorf("create module binary file: %w", err)
+	m.mu.Lock()
+	binPath, ok := m.installedExecutables[version.String()]
+	m.mu.Unlock()
+	if m.provider.Name() == "local" {
+		if !ok {
+			return "", false, fmt.Errorf("no matching binary found")
 		}
-		defer func() {
-			os.Remove(tempBinPath)
-		}()
+		return binPath, false, nil
+	}
+	if ok && !force && m.verifyExecutable(binPath, version) {
+		return binPath, false, nil
+	}
 
-		err = m.provider.DownloadModuleBinary(file, m.providerVars, version)
-		file.Close()
-		if err != nil {
-			return "", fmt.Errorf("download module binary: %s", err)
-		}
+	binPath = filepath.Join(dirName, binaryName(version))
+	if runtime.GOOS == "windows" {
+		binPath += ".exe"
+	}
+	err = m.installExactVersion(version, binPath, progress)
+	if err != nil {
+		return "", false, err
+	}
+	return binPath, true, nil
+}
 
-		binPath = filepath.Join(dirName, strings.ReplaceAll(version.String(), ".", "-"))
-		if runtime.GOOS == "windows" {
-			binPath += ".exe"
-		}
+// installExactVersion downloads the module binary for the exact version to binPath.
+// The download progress is reported to progress or, if it is nil, to feedback.
+func (m *Module) installExactVersion(version versions.Version, binPath string, progress downloadProgressFunc) error {
+	file, err := os.CreateTemp(filepath.Dir(binPath), binaryName(version)+"-*.temp")
+	if err != nil {
+		return fmt.Errorf("create module binary file: %w", err)
+	}
+	tempBinPath := file.Name()
+	defer func() {
+		os.Remove(tempBinPath)
+	}()
 
-		err = os.Rename(tempBinPath, binPath)
-		if err != nil {
-			return "", fmt.Errorf("create module binary file: %w", err)
-		}
-	} else {
-		return "", fmt.Errorf("no matching binary found")
+	hash := sha256.New()
+	var target io.Writer = io.MultiWriter(file, hash)
+	if progress != nil {
+		target = &progressWriter{Writer: target, progress: progress}
+	}
+	err = m.source.DownloadModuleBinary(target, version)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("download module binary: %s", err)
+	}
+	err = os.Chmod(tempBinPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create module binary file: %w", err)
 	}
 
-	return binPath, nil
-}
+	err = os.Rename(tempBinPath, binPath)
+	if err != nil {
+		return fmt.Errorf("create module binary file: %w", err)
+	}
 
-func (m *Module) findCompatibleModuleVersion(projectType ProjectType, libraryVersion versions.Version) (versions.Version, error) {
-	var versionMap map[string]string
-	switch projectType {
-	case ProjectType_CLIENT:
-		versionMap = m.clientLibToModVersions
-	case ProjectType_SERVER:
-		versionMap = m.serverLibToModVersions
+	// The manifest is written after the binary is in place, so that it never describes a binary which does not exist.
+	// A binary without a manifest is still usable.
+	err = writeInstallManifest(binPath, installManifest{
+		Source:      m.sourceConfig(),
+		Version:     version,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		InstalledAt: time.Now().UTC(),
+	})
+	if err != nil {
+		feedback.Warn(FeedbackPkg, "Failed to write the install manifest of the %s module binary version %s: %s", m.Lang, version, err)
 	}
+
+	m.mu.Lock()
+	m.installedExecutables[version.String()] = binPath
+	m.verifiedExecutables[binPath] = true
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Module) findCompatibleModuleVersion(projectType string, libraryVersion versions.Version) (versions.Version, error) {
+	versionMap := m.libToModVersions[projectType]
 	if versionMap == nil {
 		return nil, ErrUnsupportedProjectType
 	}
@@ -80,14 +127,8 @@ func (m *Module) findCompatibleModuleVersion(projectType ProjectType, libraryVer
 	return v, nil
 }
 
-func (m *Module) findLatestModuleVersion(projectType ProjectType) (versions.Version, error) {
-	var versionMap map[string]string
-	switch projectType {
-	case ProjectType_CLIENT:
-		versionMap = m.clientLibToModVersions
-	case ProjectType_SERVER:
-		versionMap = m.serverLibToModVersions
-	}
+func (m *Module) findLatestModuleVersion(projectType string) (versions.Version, error) {
+	versionMap := m.libToModVersions[projectType]
 	if versionMap == nil {
 		return nil, ErrUnsupportedProjectType
 	}
@@ -116,14 +157,8 @@ func (m *Module) findLatestModuleVersion(projectType ProjectType) (versions.Vers
 	return modVersion, nil
 }
 
-func (m *Module) findLibraryVersionByCGVersion(projectType ProjectType, cgVersion versions.Version) (versions.Version, error) {
-	var versionMap map[string]string
-	switch projectType {
-	case ProjectType_CLIENT:
-		versionMap = m.clientCGToLibVersions
-	case ProjectType_SERVER:
-		versionMap = m.serverCGToLibVersions
-	}
+func (m *Module) findLibraryVersionByCGVersion(projectType string, cgVersion versions.Version) (versions.Version, error) {
+	versionMap := m.cgToLibVersions[projectType]
 	if versionMap == nil {
 		return nil, ErrUnsupportedProjectType
 	}
@@ -135,18 +170,61 @@ func (m *Module) findLibraryVersionByCGVersion(projectType ProjectType, cgVersio
 	return v, nil
 }
 
-func installedBinaries(lang string) map[string]string { // version -> path
-	entries, err := os.ReadDir(filepath.Join(moduleBinPath, lang))
-	if err != nil {
-		return make(map[string]string)
+// downloadProgressFunc receives the progress of the download identified by key.
+type downloadProgressFunc func(key string, current, size int64)
+
+// progressWriter is the target of a module binary download, whose progress is reported to progress instead of feedback.
+type progressWriter struct {
+	io.Writer
+	progress downloadProgressFunc
+}
+
+// reportsProgress returns true if the progress of downloads into target is reported by target.
+func reportsProgress(target io.Writer) bool {
+	_, ok := target.(*progressWriter)
+	return ok
+}
+
+// downloadProgressReader reports the progress of reading a module binary download of a known size.
+type downloadProgressReader struct {
+	r        io.Reader
+	key      string
+	message  string
+	current  int64
+	size     int64
+	progress downloadProgressFunc
+}
+
+// newDownloadProgressReader returns a reader, which reports the progress of reading r as the download of the module binary name
+// into target. key identifies the download (e.g. its URL). r is returned unchanged if size is unknown (<= 0).
+func newDownloadProgressReader(r io.Reader, target io.Writer, key, name string, size int64) io.Reader {
+	if size <= 0 {
+		return r
+	}
+	reader := &downloadProgressReader{
+		r:       r,
+		key:     "download " + key,
+		message: "Downloading module " + name,
+		size:    size,
+	}
+	if w, ok := target.(*progressWriter); ok {
+		reader.progress = w.progress
 	}
+	return reader
+}
 
-	binaries := make(map[string]string, len(entries))
-	for _, e := range entries {
-		// TODO: validate file name (must be: major-minor-patch)
-		if !e.IsDir() {
-			binaries[strings.ReplaceAll(strings.TrimSuffix(e.Name(), ".exe"), "-", ".")] = filepath.Join(moduleBinPath, lang, e.Name())
+func (p *downloadProgressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 && p.current < p.size {
+		p.current += int64(n)
+		if p.current > p.size {
+			p.current = p.size
+		}
+		if p.progress != nil {
+			p.progress(p.key, p.current, p.size)
+		} else {
+			feedback.Progress(FeedbackPkg, p.key, p.message, p.current, p.size, cli.UnitFileSize)
 		}
 	}
-	return binaries
+	return n, err
 }
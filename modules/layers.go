@@ -0,0 +1,206 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Reserved keys of language modules config files. Their values are a location or a list of locations of other
+// config files. The files in 'extends' are merged below the entries of the file, the files in 'include' on top of them.
+// Language entries are deep-merged like JSON merge patches (RFC 7386), so a layer can override e.g. only 'source'
+// or remove a language by setting it to null.
+const (
+	layerKeyExtends = "extends"
+	layerKeyInclude = "include"
+)
+
+const maxLayerDepth = 16
+
+// loadLayeredConfig reads the language modules config file at location with all of its layers
+// and returns the merged language entries. Relative paths in every layer are resolved relative to the layer.
+func loadLayeredConfig(location string) (map[string]any, error) {
+	return loadLayer(location, nil)
+}
+
+func loadLayer(location string, stack []string) (map[string]any, error) {
+	for _, l := range stack {
+		if l == location {
+			return nil, fmt.Errorf("include cycle: %s", location)
+		}
+	}
+	if len(stack) >= maxLayerDepth {
+		return nil, fmt.Errorf("too many nested layers at %s", location)
+	}
+	stack = append(stack, location)
+
+	file, err := openLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	var layer map[string]any
+	err = decoder.Decode(&layer)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", location, err)
+	}
+
+	extends, err := layerLocations(layer, layerKeyExtends, location)
+	if err != nil {
+		return nil, err
+	}
+	include, err := layerLocations(layer, layerKeyInclude, location)
+	if err != nil {
+		return nil, err
+	}
+
+	for lang, entry := range layer {
+		if entry, ok := entry.(map[string]any); ok {
+			err = normalizeLayerPaths(entry, location)
+			if err != nil {
+				return nil, fmt.Errorf("%s: lang '%s': %w", location, lang, err)
+			}
+		}
+	}
+
+	merged := make(map[string]any)
+	for _, l := range extends {
+		base, err := loadLayer(l, stack)
+		if err != nil {
+			return nil, fmt.Errorf("extends %s: %w", l, err)
+		}
+		mergePatch(merged, base)
+	}
+	mergePatch(merged, layer)
+	for _, l := range include {
+		overlay, err := loadLayer(l, stack)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", l, err)
+		}
+		mergePatch(merged, overlay)
+	}
+	return merged, nil
+}
+
+// layerLocations removes the reserved key from layer and returns its resolved locations.
+func layerLocations(layer map[string]any, key, base string) ([]string, error) {
+	value, ok := layer[key]
+	if !ok {
+		return nil, nil
+	}
+	delete(layer, key)
+
+	var locations []string
+	switch value := value.(type) {
+	case string:
+		locations = []string{value}
+	case []any:
+		for _, v := range value {
+			l, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: value of '%s' field must be a string or a string list", base, key)
+			}
+			locations = append(locations, l)
+		}
+	default:
+		return nil, fmt.Errorf("%s: value of '%s' field must be a string or a string list", base, key)
+	}
+
+	for i, l := range locations {
+		resolved, err := resolveLocation(l, base)
+		if err != nil {
+			return nil, fmt.Errorf("%s: field '%s': %w", base, key, err)
+		}
+		locations[i] = resolved
+	}
+	return locations, nil
+}
+
+// normalizeLayerPaths resolves the locations in a language entry relative to the layer at base,
+// so that they stay valid after merging the entry with entries of other layers.
+func normalizeLayerPaths(entry map[string]any, base string) error {
+	for _, field := range []string{"library_to_module_versions", "codegame_to_library_versions"} {
+		switch value := entry[field].(type) {
+		case string:
+			resolved, err := resolveLocation(value, base)
+			if err != nil {
+				return fmt.Errorf("field '%s': %w", field, err)
+			}
+			entry[field] = resolved
+		case map[string]any:
+			for projectType, versionMap := range value {
+				if location, ok := versionMap.(string); ok {
+					resolved, err := resolveLocation(location, base)
+					if err != nil {
+						return fmt.Errorf("field '%s.%s': %w", field, projectType, err)
+					}
+					value[projectType] = resolved
+				}
+			}
+		}
+	}
+
+	if source, ok := entry["source"].(map[string]any); ok {
+		err := resolvePathVars(source, allPathVars(), base)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allPathVars returns the names of the provider vars containing local paths of all providers.
+// The provider of an entry may be defined in another layer.
+func allPathVars() []string {
+	names := make(map[string]bool)
+	for _, p := range providers {
+		if p, ok := p.(pathProvider); ok {
+			for _, n := range p.pathVars() {
+				names[n] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(names))
+	for n := range names {
+		result = append(result, n)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// mergePatch applies patch to target like a JSON merge patch (RFC 7386).
+func mergePatch(target, patch map[string]any) {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		patchObj, ok := value.(map[string]any)
+		if !ok {
+			target[key] = value
+			continue
+		}
+		targetObj, ok := target[key].(map[string]any)
+		if !ok {
+			targetObj = make(map[string]any)
+		} else {
+			// do not modify objects of other layers
+			targetObj = copyObject(targetObj)
+		}
+		mergePatch(targetObj, patchObj)
+		target[key] = targetObj
+	}
+}
+
+func copyObject(obj map[string]any) map[string]any {
+	c := make(map[string]any, len(obj))
+	for k, v := range obj {
+		if o, ok := v.(map[string]any); ok {
+			v = copyObject(o)
+		}
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,125 @@
+package modules
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Layers(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shared/lang_modules.json": `{
+			"go": {
+				"display_name": "Go",
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+				"library_to_module_versions": {"client": {"0.9": "0.4"}},
+				"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+			},
+			"js": {
+				"display_name": "JavaScript",
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "js-module"},
+				"library_to_module_versions": "versions.json",
+				"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+			},
+			"java": {
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "java-module"},
+				"library_to_module_versions": {},
+				"codegame_to_library_versions": {}
+			}
+		}`,
+		"shared/versions.json": `{"client": {"0.9": "0.2"}}`,
+		"user/lang_modules.json": `{
+			"extends": "../shared/lang_modules.json",
+			"include": ["local.json"],
+			"go": {
+				"source": {"repository": "go-module-fork"},
+				"library_to_module_versions": {"client": {"0.10": "0.5"}}
+			},
+			"java": null
+		}`,
+		"user/local.json": `{
+			"go": {"display_name": "Go (local)"}
+		}`,
+	})
+
+	registry := NewRegistry(filepath.Join(dir, "user", "lang_modules.json"))
+	langs := registry.AvailableLanguages()
+	if len(langs) != 2 || langs["go"].DisplayName != "Go (local)" {
+		t.Errorf("AvailableLanguages = %v, want go (with local display name) and js", langs)
+	}
+
+	goModule, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule(go): %s", err)
+	}
+	if goModule.providerVars["owner"] != "code-game-project" || goModule.providerVars["repository"] != "go-module-fork" {
+		t.Errorf("go source = %v, want merged source", goModule.providerVars)
+	}
+	if goModule.libToModVersions["client"]["0.9"] != "0.4" || goModule.libToModVersions["client"]["0.10"] != "0.5" {
+		t.Errorf("go library versions = %v, want merged versions", goModule.libToModVersions["client"])
+	}
+
+	js, err := registry.LoadModule("js")
+	if err != nil {
+		t.Fatalf("LoadModule(js): %s", err)
+	}
+	if js.libToModVersions["client"]["0.9"] != "0.2" {
+		t.Errorf("js library versions = %v, want versions.json relative to the shared config", js.libToModVersions["client"])
+	}
+
+	effective, err := registry.EffectiveConfig()
+	if err != nil {
+		t.Fatalf("EffectiveConfig: %s", err)
+	}
+	var config map[string]map[string]any
+	err = json.Unmarshal(effective, &config)
+	if err != nil {
+		t.Fatalf("decode effective config: %s", err)
+	}
+	if want := filepath.Join(dir, "shared", "versions.json"); config["js"]["library_to_module_versions"] != want {
+		t.Errorf("effective js library_to_module_versions = %v, want %s", config["js"]["library_to_module_versions"], want)
+	}
+	if _, ok := config["extends"]; ok {
+		t.Error("effective config contains 'extends'")
+	}
+}
+
+func TestRegistry_LayerCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.json": `{"extends": "b.json"}`,
+		"b.json": `{"extends": "a.json"}`,
+	})
+	_, err := NewRegistry(filepath.Join(dir, "a.json")).EffectiveConfig()
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("EffectiveConfig error = %v, want include cycle", err)
+	}
+}
+
+func TestRegistry_RemoteLayerEnvironment(t *testing.T) {
+	t.Setenv("CG_TEST_SECRET", "secret")
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Write([]byte(`{"include": "/${CG_TEST_SECRET}.json"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"lang_modules.json": `{"extends": "` + server.URL + `/remote.json"}`,
+	})
+	_, err := NewRegistry(filepath.Join(dir, "lang_modules.json")).EffectiveConfig()
+	if err == nil || !strings.Contains(err.Error(), "environment variables") {
+		t.Errorf("EffectiveConfig error = %v, want rejected environment variable", err)
+	}
+	for _, path := range requested {
+		if strings.Contains(path, "secret") {
+			t.Errorf("requested %s, want environment variable not to be expanded", path)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package modules
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyResourceLimits sets the resource limits of the already started process with pid.
+// The process runs without the limits until they are set (see ResourceLimits).
+func applyResourceLimits(pid int, limits ResourceLimits) error {
+	set := func(resource int, value uint64) error {
+		if value == 0 {
+			return nil
+		}
+		return unix.Prlimit(pid, resource, &unix.Rlimit{Cur: value, Max: value}, nil)
+	}
+	if err := set(unix.RLIMIT_CPU, limits.CPUSeconds); err != nil {
+		return fmt.Errorf("limit cpu time: %w", err)
+	}
+	if err := set(unix.RLIMIT_AS, limits.MemoryBytes); err != nil {
+		return fmt.Errorf("limit memory: %w", err)
+	}
+	if err := set(unix.RLIMIT_NOFILE, limits.OpenFiles); err != nil {
+		return fmt.Errorf("limit open files: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package modules
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+var warnResourceLimitsOnce sync.Once
+
+// applyResourceLimits does not limit the process, because resource limits are only supported on Linux.
+// It warns once per CLI run, so that configuring limits does not break modules on other platforms.
+func applyResourceLimits(pid int, limits ResourceLimits) error {
+	warnResourceLimitsOnce.Do(func() {
+		feedback.Warn(FeedbackPkg, "Resource limits for modules are not supported on %s. Modules run without limits.", runtime.GOOS)
+	})
+	return nil
+}
@@ -0,0 +1,237 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// binaryNameRegexp matches the file names of installed module binaries without the '.exe' suffix (e.g. 1-2-3).
+var binaryNameRegexp = regexp.MustCompile(`^\d+(-\d+){0,2}$`)
+
+type InstallStatus string
+
+const (
+	// The checksum of the binary matches its install manifest.
+	InstallOK InstallStatus = "ok"
+	// The binary has no install manifest (e.g. installed by an older version of the CLI).
+	InstallUnknown InstallStatus = "unknown"
+	// The checksum of the binary does not match its install manifest or the manifest is invalid.
+	InstallCorrupt InstallStatus = "corrupt"
+	// The file name is not a valid module version (e.g. leftovers of an interrupted download).
+	InstallInvalid InstallStatus = "invalid"
+)
+
+// Installation is a file in the module binary directory of a language.
+type Installation struct {
+	Path string
+	// nil if Status is InstallInvalid
+	Version versions.Version
+	Status  InstallStatus
+	// zero if Status is InstallUnknown or InstallInvalid
+	InstalledAt time.Time
+}
+
+// installManifest is stored next to each installed module binary.
+type installManifest struct {
+	Source      map[string]any   `json:"source"`
+	Version     versions.Version `json:"version"`
+	SHA256      string           `json:"sha256"`
+	InstalledAt time.Time        `json:"installed_at"`
+}
+
+func binaryName(version versions.Version) string {
+	return strings.ReplaceAll(version.String(), ".", "-")
+}
+
+func manifestPath(binPath string) string {
+	return strings.TrimSuffix(binPath, ".exe") + ".manifest.json"
+}
+
+func writeInstallManifest(binPath string, manifest installManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath(binPath), data, 0o644)
+}
+
+// sourceConfig returns the 'source' object of the module without credentials.
+func (m *Module) sourceConfig() map[string]any {
+	source := PublicSource(m.providerVars)
+	source["provider"] = m.provider.Name()
+	return source
+}
+
+// scanInstallations returns all files in the module binary directory of lang except for install manifests.
+func scanInstallations(lang string) []Installation {
+	installations := listInstallations(lang)
+	for i := range installations {
+		if installations[i].Status != InstallInvalid {
+			installations[i].Status, installations[i].InstalledAt = verifyInstallation(installations[i].Path, installations[i].Version)
+		}
+	}
+	return installations
+}
+
+// listInstallations is like scanInstallations but does not verify the binaries. Their status is empty.
+func listInstallations(lang string) []Installation {
+	dir := filepath.Join(moduleBinPath, lang)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	installations := make([]Installation, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".manifest.json") {
+			continue
+		}
+		installation := Installation{
+			Path:   filepath.Join(dir, e.Name()),
+			Status: InstallInvalid,
+		}
+		name := e.Name()
+		if runtime.GOOS == "windows" {
+			name = strings.TrimSuffix(name, ".exe")
+		}
+		if binaryNameRegexp.MatchString(name) {
+			installation.Version, err = versions.Parse(strings.ReplaceAll(name, "-", "."))
+			if err == nil {
+				installation.Status = ""
+			}
+		}
+		installations = append(installations, installation)
+	}
+	return installations
+}
+
+func verifyInstallation(binPath string, version versions.Version) (InstallStatus, time.Time) {
+	data, err := os.ReadFile(manifestPath(binPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return InstallUnknown, time.Time{}
+		}
+		return InstallCorrupt, time.Time{}
+	}
+	var manifest installManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil || versions.Compare(manifest.Version, version) != 0 {
+		return InstallCorrupt, time.Time{}
+	}
+
+	file, err := os.Open(binPath)
+	if err != nil {
+		return InstallCorrupt, time.Time{}
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil || hex.EncodeToString(hash.Sum(nil)) != manifest.SHA256 {
+		return InstallCorrupt, time.Time{}
+	}
+	return InstallOK, manifest.InstalledAt
+}
+
+// installedBinaries returns all module binaries of lang without verifying their checksums (see Module.verifyExecutable).
+func installedBinaries(lang string) map[string]string { // version -> path
+	installations := listInstallations(lang)
+	binaries := make(map[string]string, len(installations))
+	for _, i := range installations {
+		if i.Status != InstallInvalid {
+			binaries[i.Version.String()] = i.Path
+		}
+	}
+	return binaries
+}
+
+// verifyExecutable returns false if the installed module binary at binPath is corrupt or cannot be verified,
+// so that it is downloaded again.
+// The checksum of each binary is only verified once.
+func (m *Module) verifyExecutable(binPath string, version versions.Version) bool {
+	m.mu.Lock()
+	verified := m.verifiedExecutables[binPath]
+	m.mu.Unlock()
+	if verified {
+		return true
+	}
+	status, _ := verifyInstallation(binPath, version)
+	switch status {
+	case InstallCorrupt:
+		feedback.Warn(FeedbackPkg, "The %s module binary version %s is corrupt and will be reinstalled.", m.Lang, version)
+		return false
+	case InstallUnknown:
+		// binaries installed before install manifests existed cannot be verified
+		feedback.Info(FeedbackPkg, "The %s module binary version %s has no install manifest and will be reinstalled.", m.Lang, version)
+		return false
+	}
+	m.mu.Lock()
+	m.verifiedExecutables[binPath] = true
+	m.mu.Unlock()
+	return true
+}
+
+// Installations returns the state of all files in the module binary directory.
+// It returns nil for local modules.
+func (m *Module) Installations() []Installation {
+	if m.provider.Name() == "local" {
+		return nil
+	}
+	return scanInstallations(m.Lang)
+}
+
+// Reinstall downloads the module binary for version even if it is already installed.
+// Like other actions, it installs the latest exact version matching version.
+func (m *Module) Reinstall(version versions.Version) (string, error) {
+	binPath, _, err := m.installVersion(version, true, nil)
+	return binPath, err
+}
+
+// Temporary files of downloads are only removed by Repair if they are older than tempFileGracePeriod,
+// so that downloads of other processes are not interrupted.
+var tempFileGracePeriod = time.Hour
+
+// Repair removes invalid files from the module binary directory and reinstalls corrupt and unknown binaries
+// with their exact version at their path. It returns the installations, which were repaired or removed.
+func (m *Module) Repair() ([]Installation, error) {
+	var repaired []Installation
+	var errs []error
+	for _, i := range m.Installations() {
+		switch i.Status {
+		case InstallInvalid:
+			if strings.HasSuffix(strings.TrimSuffix(i.Path, ".exe"), ".temp") {
+				stat, err := os.Stat(i.Path)
+				if err == nil && time.Since(stat.ModTime()) < tempFileGracePeriod {
+					continue
+				}
+			}
+			err := os.Remove(i.Path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("remove '%s': %w", i.Path, err))
+				continue
+			}
+		case InstallCorrupt, InstallUnknown:
+			err := m.installExactVersion(i.Version, i.Path, nil)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("reinstall version %s: %w", i.Version, err))
+				continue
+			}
+		default:
+			continue
+		}
+		repaired = append(repaired, i)
+	}
+	return repaired, errors.Join(errs...)
+}
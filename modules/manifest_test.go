@@ -0,0 +1,165 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func Test_scanInstallations(t *testing.T) {
+	oldBinPath := moduleBinPath
+	moduleBinPath = t.TempDir()
+	defer func() {
+		moduleBinPath = oldBinPath
+	}()
+	dir := filepath.Join(moduleBinPath, "go")
+	os.MkdirAll(dir, 0o755)
+
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	writeBinary := func(name, content string) string {
+		path := filepath.Join(dir, name+exe)
+		if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	writeManifest := func(binPath, version, content string) {
+		sum := sha256.Sum256([]byte(content))
+		err := writeInstallManifest(binPath, installManifest{
+			Version:     versions.MustParse(version),
+			SHA256:      hex.EncodeToString(sum[:]),
+			InstalledAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeManifest(writeBinary("1-2-3", "complete"), "1.2.3", "complete")
+	writeManifest(writeBinary("1-2-4", "trunc"), "1.2.4", "truncated")
+	writeBinary("1-2-5", "old")
+	writeBinary("1-2-6-1234.temp", "")
+
+	want := map[string]InstallStatus{
+		"1-2-3" + exe:           InstallOK,
+		"1-2-4" + exe:           InstallCorrupt,
+		"1-2-5" + exe:           InstallUnknown,
+		"1-2-6-1234.temp" + exe: InstallInvalid,
+	}
+	installations := scanInstallations("go")
+	if len(installations) != len(want) {
+		t.Fatalf("scanInstallations = %d installations, want %d", len(installations), len(want))
+	}
+	for _, i := range installations {
+		if status := want[filepath.Base(i.Path)]; i.Status != status {
+			t.Errorf("%s: status = %s, want %s", filepath.Base(i.Path), i.Status, status)
+		}
+	}
+
+	binaries := installedBinaries("go")
+	if len(binaries) != 3 {
+		t.Errorf("installedBinaries = %v, want versions 1.2.3, 1.2.4 and 1.2.5", binaries)
+	}
+
+	m := &Module{Lang: "go", verifiedExecutables: make(map[string]bool)}
+	for version, ok := range map[string]bool{"1.2.3": true, "1.2.4": false, "1.2.5": false} {
+		if verified := m.verifyExecutable(binaries[version], versions.MustParse(version)); verified != ok {
+			t.Errorf("verifyExecutable(%s) = %t, want %t", version, verified, ok)
+		}
+	}
+	if m.verifiedExecutables[binaries["1.2.4"]] {
+		t.Errorf("corrupt binary was marked as verified")
+	}
+}
+
+func TestModule_sourceConfig(t *testing.T) {
+	source := map[string]any{
+		"provider": "gitlab",
+		"project":  "group/go-module",
+		"token":    "secret",
+	}
+	prov, providerVars, _, err := parseSource(source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Module{provider: prov, providerVars: providerVars}
+
+	got := m.sourceConfig()
+	if _, ok := got["token"]; ok {
+		t.Errorf("sourceConfig contains the token: %v", got)
+	}
+	if got["provider"] != "gitlab" || got["project"] != "group/go-module" {
+		t.Errorf("sourceConfig = %v, want provider and project", got)
+	}
+	if source["token"] != "secret" {
+		t.Error("sourceConfig modified the source object")
+	}
+}
+
+func TestModule_Repair(t *testing.T) {
+	oldBinPath := moduleBinPath
+	moduleBinPath = t.TempDir()
+	defer func() {
+		moduleBinPath = oldBinPath
+	}()
+
+	layout := newOCITestLayout(t, "application/vnd.codegame.module.binary", []byte("module binary"), "v0.4.0", "v0.4.1")
+	m, err := newTestRegistry(t, fmt.Sprintf(`{
+		"go": {
+			"source": {"provider": "oci", "layout": %q},
+			"library_to_module_versions": {"client": {"0.9": "0.4"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+		}
+	}`, layout.dir)).LoadModule("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(moduleBinPath, "go")
+	os.MkdirAll(dir, 0o755)
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	writeFiles(t, dir, map[string]string{
+		"0-4" + exe:          "without manifest",
+		"garbage":            "",
+		"0-4-1-1.temp" + exe: "",
+		"0-4-1-2.temp" + exe: "",
+	})
+	old := time.Now().Add(-2 * tempFileGracePeriod)
+	os.Chtimes(filepath.Join(dir, "0-4-1-1.temp"+exe), old, old)
+
+	repaired, err := m.Repair()
+	if err != nil {
+		t.Fatalf("Repair: %s", err)
+	}
+	if len(repaired) != 3 {
+		t.Errorf("repaired = %+v, want the binary, the garbage and the old temporary file", repaired)
+	}
+
+	var manifest installManifest
+	data, err := os.ReadFile(manifestPath(filepath.Join(dir, "0-4"+exe)))
+	if err == nil {
+		err = json.Unmarshal(data, &manifest)
+	}
+	if err != nil || manifest.Version.String() != "0.4" {
+		t.Errorf("manifest of 0-4 = %+v, %v, want reinstalled version 0.4", manifest, err)
+	}
+	for name, want := range map[string]bool{"0-4-1" + exe: false, "garbage": false, "0-4-1-1.temp" + exe: false, "0-4-1-2.temp" + exe: true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s exists = %t, want %t", name, err == nil, want)
+		}
+	}
+}
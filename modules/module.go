@@ -6,14 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
+	"sync"
 
-	"github.com/code-game-project/cli-utils/config"
 	"github.com/code-game-project/cli-utils/feedback"
-	"github.com/code-game-project/cli-utils/request"
 )
 
 const FeedbackPkg = feedback.Package("modules")
@@ -23,87 +18,58 @@ var (
 	ErrUnsupportedCodeGameVersion = errors.New("unsupported codegame version")
 )
 
-var modules = make(map[string]*Module)
-
 type Module struct {
-	Lang                   string
-	DisplayName            string
-	clientCGToLibVersions  map[string]string // CodeGame version -> library version
-	serverCGToLibVersions  map[string]string // CodeGame version -> library version
-	clientLibToModVersions map[string]string // client library version -> module version
-	serverLibToModVersions map[string]string // server library version -> module version
-	installedExecutables   map[string]string // module version -> executable path
+	Lang                 string
+	DisplayName          string
+	cgToLibVersions      map[string]map[string]string // project type -> CodeGame version -> library version
+	libToModVersions     map[string]map[string]string // project type -> library version -> module version
+	installedExecutables map[string]string            // module version -> executable path
+	verifiedExecutables  map[string]bool              // executable path -> checksum verified
+	infos                map[string]ModuleInfo        // executable path -> info response
+	mu                   sync.Mutex                   // protects installedExecutables, verifiedExecutables and infos
 
 	provider     provider
-	providerVars map[string]any
+	providerVars map[string]any // 'source' object without 'provider'
+	source       Source
+	envPolicy    *EnvironmentPolicy
 }
 
-var rawModules map[string]rawModule
-
 type rawModule struct {
-	DisplayName               string          `json:"display_name"`
-	Source                    map[string]any  `json:"source"`
-	LibraryToModuleVersions   json.RawMessage `json:"library_to_module_versions"`
-	CodeGameToLibraryVersions json.RawMessage `json:"codegame_to_library_versions"`
+	DisplayName               string             `json:"display_name"`
+	Source                    map[string]any     `json:"source"`
+	LibraryToModuleVersions   json.RawMessage    `json:"library_to_module_versions"`
+	CodeGameToLibraryVersions json.RawMessage    `json:"codegame_to_library_versions"`
+	Environment               *EnvironmentPolicy `json:"environment,omitempty"`
+
+	// file path or URL of the config file containing the module, used to resolve relative paths
+	base string
 }
 
-func LoadModule(lang string) (*Module, error) {
-	if rawModules == nil {
-		err := loadModules()
-		if err != nil {
-			return nil, err
-		}
-	}
-	if m, ok := modules[lang]; ok {
-		return m, nil
-	}
-	m, ok := rawModules[lang]
-	if !ok {
-		return nil, fmt.Errorf("no module available for lang '%s'", lang)
-	}
-
+func newModule(lang string, m rawModule) (*Module, error) {
 	if m.Source == nil {
 		return nil, errors.New("missing 'source' field")
 	}
 
 	module := &Module{
-		Lang:                   lang,
-		DisplayName:            m.DisplayName,
-		providerVars:           make(map[string]any),
-		clientLibToModVersions: make(map[string]string),
-		clientCGToLibVersions:  make(map[string]string),
-		serverLibToModVersions: make(map[string]string),
-		serverCGToLibVersions:  make(map[string]string),
-		installedExecutables:   make(map[string]string),
-	}
-
-	providerNameAny, ok := m.Source["provider"]
-	if !ok {
-		return nil, errors.New("missing 'source.provider' field")
-	}
-	providerName, ok := providerNameAny.(string)
-	if !ok {
-		return nil, errors.New("value of 'source.provider' field must be a string")
+		Lang:                 lang,
+		DisplayName:          m.DisplayName,
+		cgToLibVersions:      make(map[string]map[string]string),
+		libToModVersions:     make(map[string]map[string]string),
+		installedExecutables: make(map[string]string),
+		verifiedExecutables:  make(map[string]bool),
+		infos:                make(map[string]ModuleInfo),
+		envPolicy:            m.Environment,
 	}
 
-	prov, ok := providers[providerName]
-	if !ok {
-		return nil, fmt.Errorf("unknown module provider: %s", providerName)
+	prov, providerVars, source, err := parseSource(m.Source, m.base)
+	if err != nil {
+		return nil, err
 	}
 	module.provider = prov
+	module.providerVars = providerVars
+	module.source = source
 
-	for n, v := range m.Source {
-		if n != "provider" {
-			module.providerVars[n] = v
-		}
-	}
-
-	errs := prov.ValidateProviderVars(module.providerVars)
-	if len(errs) > 0 {
-		return nil, fmt.Errorf("invalid module source: %s", strings.Join(errs, ", "))
-	}
-
-	err := module.loadVersions(m.LibraryToModuleVersions, m.CodeGameToLibraryVersions)
+	err = module.loadVersions(m.LibraryToModuleVersions, m.CodeGameToLibraryVersions, m.base)
 	if err != nil {
 		return nil, err
 	}
@@ -113,22 +79,20 @@ func LoadModule(lang string) (*Module, error) {
 		return nil, fmt.Errorf("load installed versions: %w", err)
 	}
 
-	modules[lang] = module
-
 	return module, nil
 }
 
-func (m *Module) loadVersions(libraryToModuleVersions, codegameToLibraryVersions json.RawMessage) error {
+func (m *Module) loadVersions(libraryToModuleVersions, codegameToLibraryVersions json.RawMessage, base string) error {
 	var err error
 
 	if m.provider.Name() != "local" {
-		m.clientLibToModVersions, m.serverLibToModVersions, err = loadVersionMap(libraryToModuleVersions)
+		m.libToModVersions, err = loadVersionMap(libraryToModuleVersions, base, "library_to_module_versions")
 		if err != nil {
 			return fmt.Errorf("load library version compatibility list: %w", err)
 		}
 	}
 
-	m.clientCGToLibVersions, m.serverCGToLibVersions, err = loadVersionMap(codegameToLibraryVersions)
+	m.cgToLibVersions, err = loadVersionMap(codegameToLibraryVersions, base, "codegame_to_library_versions")
 	if err != nil {
 		return fmt.Errorf("load codegame version compatibility list: %w", err)
 	}
@@ -136,35 +100,40 @@ func (m *Module) loadVersions(libraryToModuleVersions, codegameToLibraryVersions
 	return nil
 }
 
-func loadVersionMap(jsonData json.RawMessage) (client, server map[string]string, err error) {
-	type versionsObj struct {
-		Client json.RawMessage `json:"client"`
-		Server json.RawMessage `json:"server"`
-	}
-
-	versions, err := loadJSONObjectInlineOrLocalOrRemote[versionsObj](jsonData)
+// loadVersionMap loads the version maps of all project types in the field with fieldName.
+// Project types with a null version map are not supported.
+func loadVersionMap(jsonData json.RawMessage, base, fieldName string) (map[string]map[string]string, error) {
+	projectTypes, base, err := loadJSONObjectInlineOrLocalOrRemote[map[string]json.RawMessage](jsonData, base)
 	if err != nil {
-		return nil, nil, err
+		return nil, fmt.Errorf("field '%s': %w", fieldName, err)
 	}
 
-	if versions.Client != nil {
-		client, err = loadJSONObjectInlineOrLocalOrRemote[map[string]string](versions.Client)
-		if err != nil {
-			return nil, nil, err
+	versionMaps := make(map[string]map[string]string, len(projectTypes))
+	for projectType, data := range projectTypes {
+		if !ValidProjectTypeName(projectType) {
+			return nil, fmt.Errorf("field '%s.%s': invalid project type name", fieldName, projectType)
 		}
-	}
-
-	if versions.Server != nil {
-		server, err = loadJSONObjectInlineOrLocalOrRemote[map[string]string](versions.Server)
+		versionMap, _, err := loadJSONObjectInlineOrLocalOrRemote[map[string]string](data, base)
 		if err != nil {
-			return nil, nil, err
+			return nil, fmt.Errorf("field '%s.%s': %w", fieldName, projectType, err)
+		}
+		if versionMap != nil {
+			versionMaps[projectType] = versionMap
 		}
 	}
+	return versionMaps, nil
+}
 
-	return client, server, nil
+// LoadJSON decodes data or, if data is a JSON string, the local or remote file at the location it references.
+// Locations are resolved like in lang_modules.json files. Relative locations are resolved relative to base.
+func LoadJSON[T any](data json.RawMessage, base string) (T, error) {
+	object, _, err := loadJSONObjectInlineOrLocalOrRemote[T](data, base)
+	return object, err
 }
 
-func loadJSONObjectInlineOrLocalOrRemote[T any](jsonData json.RawMessage) (T, error) {
+// loadJSONObjectInlineOrLocalOrRemote decodes jsonData or, if jsonData is a string, the file at the location it references.
+// Relative locations are resolved relative to base. The location of the decoded file is returned as the base for nested locations.
+func loadJSONObjectInlineOrLocalOrRemote[T any](jsonData json.RawMessage, base string) (T, string, error) {
 	file := io.NopCloser(bytes.NewBuffer(jsonData))
 
 	var object T
@@ -172,24 +141,23 @@ func loadJSONObjectInlineOrLocalOrRemote[T any](jsonData json.RawMessage) (T, er
 	var uri string
 	err := json.Unmarshal(jsonData, &uri)
 	if err == nil {
-		if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
-			file, err = request.FetchFile(uri, 24*time.Hour, false)
-			if err != nil && !errors.Is(err, io.EOF) {
-				return object, err
-			}
-		} else {
-			file, err = os.Open(uri)
-			if err != nil {
-				return object, err
-			}
+		uri, err = resolveLocation(uri, base)
+		if err != nil {
+			return object, "", err
+		}
+		base = uri
+		file, err = openLocation(uri)
+		if err != nil {
+			return object, "", err
 		}
+		defer file.Close()
 	}
 
 	err = json.NewDecoder(file).Decode(&object)
 	if err != nil {
-		return object, err
+		return object, "", err
 	}
-	return object, nil
+	return object, base, nil
 }
 
 func (m *Module) loadInstalledVersions() error {
@@ -202,55 +170,25 @@ func (m *Module) loadInstalledVersions() error {
 	return err
 }
 
-func loadModules() error {
-	file, err := os.Open(filepath.Join(config.ConfigDir(), "lang_modules.json"))
-	if err != nil {
-		return fmt.Errorf("open language modules config file: %w", err)
-	}
-	defer file.Close()
-
-	err = json.NewDecoder(file).Decode(&rawModules)
-	if err != nil {
-		return fmt.Errorf("decode language modules config file: %w", err)
-	}
-	return nil
-}
-
 type AvailableLanguage struct {
 	DisplayName    string
 	SupportsClient bool
 	SupportsServer bool
+	// sorted names of all supported project types including client and server
+	ProjectTypes []string
+}
+
+// LoadModule loads the module for lang from DefaultRegistry.
+func LoadModule(lang string) (*Module, error) {
+	return DefaultRegistry.LoadModule(lang)
 }
 
-var availableLanguages map[string]AvailableLanguage
+// EffectiveConfig returns the merged language modules config of DefaultRegistry.
+func EffectiveConfig() (json.RawMessage, error) {
+	return DefaultRegistry.EffectiveConfig()
+}
 
+// AvailableLanguages returns all languages in DefaultRegistry.
 func AvailableLanguages() map[string]AvailableLanguage { // name -> display name
-	if rawModules == nil {
-		err := loadModules()
-		if err != nil {
-			feedback.Error(FeedbackPkg, "Failed to load available languages: %s", err)
-		}
-	}
-	if availableLanguages != nil {
-		return availableLanguages
-	}
-	availableLanguages = make(map[string]AvailableLanguage, len(rawModules))
-
-	for n, m := range rawModules {
-		type versionsObj struct {
-			Client json.RawMessage `json:"client"`
-			Server json.RawMessage `json:"server"`
-		}
-		versions, err := loadJSONObjectInlineOrLocalOrRemote[versionsObj](m.CodeGameToLibraryVersions)
-		if err != nil {
-			feedback.Error(FeedbackPkg, "Failed to load supported project types of %s module: %s", n, err)
-			continue
-		}
-		availableLanguages[n] = AvailableLanguage{
-			DisplayName:    m.DisplayName,
-			SupportsClient: versions.Client != nil,
-			SupportsServer: versions.Server != nil,
-		}
-	}
-	return availableLanguages
+	return DefaultRegistry.AvailableLanguages()
 }
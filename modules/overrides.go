@@ -0,0 +1,142 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/config"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// lang -> module override
+var userOverridesPath = filepath.Join(config.ConfigDir(), "module_overrides.json")
+
+type ResolutionSource string
+
+const (
+	ResolvedFromProject  ResolutionSource = "project"
+	ResolvedFromUser     ResolutionSource = "user"
+	ResolvedFromRegistry ResolutionSource = "registry"
+)
+
+// Resolution describes which module executable is used for a module version.
+type Resolution struct {
+	Path string
+	// nil if the executable was overridden by path without a version
+	Version versions.Version
+	Source  ResolutionSource
+}
+
+// Resolve determines the module executable to use for modVersion.
+// The override in the .codegame.json file of the current project takes precedence over the override in
+// module_overrides.json in the config directory. If neither exists, modVersion is installed from the registry.
+func (m *Module) Resolve(modVersion versions.Version) (Resolution, error) {
+	return m.resolve(modVersion, true)
+}
+
+func (m *Module) resolve(modVersion versions.Version, useProject bool) (Resolution, error) {
+	if useProject {
+		override, root, err := m.projectOverride()
+		if err != nil {
+			return Resolution{}, fmt.Errorf("load project module override: %w", err)
+		}
+		if override != nil {
+			return m.applyOverride(*override, filepath.Join(root, ".codegame.json"), ResolvedFromProject, modVersion)
+		}
+	}
+
+	override, err := m.userOverride()
+	if err != nil {
+		return Resolution{}, fmt.Errorf("load user module override: %w", err)
+	}
+	if override != nil {
+		return m.applyOverride(*override, userOverridesPath, ResolvedFromUser, modVersion)
+	}
+
+	path, err := m.install(modVersion)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("install module: %w", err)
+	}
+	return Resolution{
+		Path:    path,
+		Version: modVersion,
+		Source:  ResolvedFromRegistry,
+	}, nil
+}
+
+// applyOverride applies override, which is defined in the file at base.
+func (m *Module) applyOverride(override cgfile.ModuleOverride, base string, source ResolutionSource, modVersion versions.Version) (Resolution, error) {
+	if override.Path != "" {
+		path, err := resolveLocalPath(override.Path, base)
+		if err != nil {
+			return Resolution{}, fmt.Errorf("%s module override: %w", source, err)
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			return Resolution{}, fmt.Errorf("%s module override: %w", source, err)
+		}
+		if stat.IsDir() {
+			return Resolution{}, fmt.Errorf("%s module override: %s is a directory", source, path)
+		}
+		return Resolution{
+			Path:    path,
+			Version: override.Version,
+			Source:  source,
+		}, nil
+	}
+
+	if override.Version == nil {
+		return Resolution{}, fmt.Errorf("%s module override: missing 'version' or 'path' field", source)
+	}
+	path, err := m.install(override.Version)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("install module: %w", err)
+	}
+	return Resolution{
+		Path:    path,
+		Version: override.Version,
+		Source:  source,
+	}, nil
+}
+
+// projectOverride returns the module override of the current project if the project uses m.
+func (m *Module) projectOverride() (*cgfile.ModuleOverride, string, error) {
+	root, err := cgfile.FindProjectRoot()
+	if err != nil {
+		return nil, "", nil
+	}
+	data, err := cgfile.Load(root)
+	if err != nil {
+		return nil, "", err
+	}
+	if data.Language != m.Lang {
+		return nil, "", nil
+	}
+	return data.ModOverride, root, nil
+}
+
+func (m *Module) userOverride() (*cgfile.ModuleOverride, error) {
+	file, err := os.Open(userOverridesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var overrides map[string]cgfile.ModuleOverride
+	err = json.NewDecoder(file).Decode(&overrides)
+	if err != nil {
+		return nil, fmt.Errorf("decode module_overrides.json: %w", err)
+	}
+	override, ok := overrides[m.Lang]
+	if !ok {
+		return nil, nil
+	}
+	return &override, nil
+}
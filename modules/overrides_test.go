@@ -0,0 +1,122 @@
+package modules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/internal/testutil"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func TestResolveOverrides(t *testing.T) {
+	configDir := t.TempDir()
+	oldUserOverridesPath := userOverridesPath
+	userOverridesPath = filepath.Join(configDir, "module_overrides.json")
+	defer func() {
+		userOverridesPath = oldUserOverridesPath
+	}()
+
+	err := os.WriteFile(filepath.Join(configDir, "user-module"), nil, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(userOverridesPath, []byte(`{"go": {"path": "user-module", "version": "0.9"}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := t.TempDir()
+	err = os.WriteFile(filepath.Join(projectDir, "project-module"), nil, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = (&cgfile.CodeGameFileData{
+		GameName:    "game",
+		ProjectType: "client",
+		Language:    "go",
+		ModOverride: &cgfile.ModuleOverride{
+			Path: "project-module",
+		},
+	}).Write(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goModule := &Module{Lang: "go"}
+
+	resolution, err := goModule.Resolve(versions.MustParse("1.0"))
+	if err != nil {
+		t.Fatalf("Resolve: %s", err)
+	}
+	if resolution.Source != ResolvedFromProject || resolution.Path != filepath.Join(projectDir, "project-module") {
+		t.Errorf("Resolve = %+v, want project override", resolution)
+	}
+
+	resolution, err = goModule.resolve(versions.MustParse("1.0"), false)
+	if err != nil {
+		t.Fatalf("resolve without project: %s", err)
+	}
+	if resolution.Source != ResolvedFromUser || resolution.Path != filepath.Join(configDir, "user-module") || versions.Compare(resolution.Version, versions.MustParse("0.9")) != 0 {
+		t.Errorf("resolve without project = %+v, want user override", resolution)
+	}
+
+	// the project override only applies to modules of the project language
+	jsModule := &Module{Lang: "js"}
+	err = os.WriteFile(userOverridesPath, []byte(`{"js": {"path": "missing-module"}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = jsModule.Resolve(versions.MustParse("1.0"))
+	if err == nil {
+		t.Error("Resolve with missing override executable succeeded")
+	}
+}
+
+func TestModule_ExecCreateClient_override(t *testing.T) {
+	configDir := t.TempDir()
+	oldUserOverridesPath := userOverridesPath
+	userOverridesPath = filepath.Join(configDir, "module_overrides.json")
+	defer func() {
+		userOverridesPath = oldUserOverridesPath
+	}()
+	err := os.WriteFile(userOverridesPath, []byte(fmt.Sprintf(`{"go": {"path": %q}}`, testutil.BuildFakeModule(t))), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := newTestRegistry(t, testLangModules).LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	modVersion, err := m.ExecCreateClient("game", "play.example.com", "go", versions.MustParse("0.9"))
+	if err != nil {
+		t.Fatalf("ExecCreateClient: %s", err)
+	}
+	// the registry maps CodeGame 0.9 to module version 0.4 but the fake module reports version 0.1.0
+	if modVersion.String() != "0.1.0" {
+		t.Errorf("ExecCreateClient version = %s, want 0.1.0 of the override", modVersion)
+	}
+}
@@ -0,0 +1,121 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/cli-utils/request"
+)
+
+func isRemoteLocation(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+var envVarRegexp = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// resolveLocation expands environment variables (${NAME}), a leading ~ and file:// URLs in location.
+// Relative paths are resolved relative to base, which is the file path or URL of the file containing location.
+// If base is empty, relative paths are resolved relative to the current working directory.
+//
+// Environment variables are only expanded in local files. Locations in remote files must not reference them,
+// because a remote file could otherwise make the CLI send secrets from the environment to any host.
+func resolveLocation(location, base string) (string, error) {
+	if isRemoteLocation(base) {
+		if envVarRegexp.MatchString(location) {
+			return "", fmt.Errorf("environment variables are not allowed in locations of remote files: '%s'", location)
+		}
+	} else {
+		var undefined []string
+		location = envVarRegexp.ReplaceAllStringFunc(location, func(variable string) string {
+			name := envVarRegexp.FindStringSubmatch(variable)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				undefined = append(undefined, name)
+			}
+			return value
+		})
+		if len(undefined) > 0 {
+			return "", fmt.Errorf("undefined environment variable(s) in '%s': %s", location, strings.Join(undefined, ", "))
+		}
+	}
+
+	if isRemoteLocation(location) {
+		return location, nil
+	}
+
+	if strings.HasPrefix(location, "file://") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("invalid file URL: %w", err)
+		}
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("unsupported host in file URL '%s'", location)
+		}
+		location = u.Path
+		if runtime.GOOS == "windows" {
+			// file:///C:/path
+			location = strings.TrimPrefix(location, "/")
+		}
+		location = filepath.FromSlash(location)
+	}
+
+	if location == "~" || strings.HasPrefix(location, "~/") || strings.HasPrefix(location, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand '~': %w", err)
+		}
+		location = filepath.Join(home, location[1:])
+	}
+
+	if filepath.IsAbs(location) {
+		return filepath.Clean(location), nil
+	}
+
+	if isRemoteLocation(base) {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", fmt.Errorf("invalid base URL: %w", err)
+		}
+		ref, err := url.Parse(filepath.ToSlash(location))
+		if err != nil {
+			return "", fmt.Errorf("invalid relative URL: %w", err)
+		}
+		return baseURL.ResolveReference(ref).String(), nil
+	}
+	if base == "" {
+		return filepath.Abs(location)
+	}
+	return filepath.Join(filepath.Dir(base), location), nil
+}
+
+// resolveLocalPath is like resolveLocation but fails if the result is not a local path.
+func resolveLocalPath(location, base string) (string, error) {
+	resolved, err := resolveLocation(location, base)
+	if err != nil {
+		return "", err
+	}
+	if isRemoteLocation(resolved) {
+		return "", fmt.Errorf("'%s' is not a local path", resolved)
+	}
+	return resolved, nil
+}
+
+// openLocation opens the local file or fetches the remote file at the resolved location.
+func openLocation(location string) (io.ReadCloser, error) {
+	if isRemoteLocation(location) {
+		file, err := request.FetchFile(location, 24*time.Hour, false)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return file, nil
+	}
+	return os.Open(location)
+}
@@ -0,0 +1,108 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveLocation(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	t.Setenv("CG_TEST_DIR", filepath.FromSlash("/opt/codegame"))
+	base := filepath.FromSlash("/etc/codegame/lang_modules.json")
+
+	tests := []struct {
+		location string
+		base     string
+		want     string
+	}{
+		{"versions.json", base, filepath.FromSlash("/etc/codegame/versions.json")},
+		{"../shared/versions.json", base, filepath.FromSlash("/etc/shared/versions.json")},
+		{"~/versions.json", base, filepath.Join(home, "versions.json")},
+		{"${CG_TEST_DIR}/versions.json", base, filepath.FromSlash("/opt/codegame/versions.json")},
+		{"client.json", "https://example.com/modules/versions.json", "https://example.com/modules/client.json"},
+		{"https://example.com/versions.json", base, "https://example.com/versions.json"},
+	}
+	if filepath.Separator == '/' {
+		tests = append(tests, struct {
+			location string
+			base     string
+			want     string
+		}{"file:///srv/versions.json", base, "/srv/versions.json"})
+	}
+	for _, test := range tests {
+		got, err := resolveLocation(test.location, test.base)
+		if err != nil {
+			t.Errorf("resolveLocation(%q, %q): %s", test.location, test.base, err)
+		} else if got != test.want {
+			t.Errorf("resolveLocation(%q, %q) = %q, want %q", test.location, test.base, got, test.want)
+		}
+	}
+
+	_, err = resolveLocation("${CG_TEST_UNDEFINED}/versions.json", base)
+	if err == nil || !strings.Contains(err.Error(), "CG_TEST_UNDEFINED") {
+		t.Errorf("resolveLocation with undefined variable error = %v, want error naming the variable", err)
+	}
+
+	got, err := resolveLocation("$CG_TEST_DIR/versions.json", base)
+	if err != nil || got != filepath.FromSlash("/etc/codegame/$CG_TEST_DIR/versions.json") {
+		t.Errorf("resolveLocation with bare variable = %q, %v, want unexpanded", got, err)
+	}
+
+	t.Setenv("CG_TEST_SECRET", "secret")
+	for _, location := range []string{"https://example.com/${CG_TEST_SECRET}", "${CG_TEST_SECRET}/versions.json"} {
+		got, err := resolveLocation(location, "https://example.com/lang_modules.json")
+		if err == nil || strings.Contains(got, "secret") {
+			t.Errorf("resolveLocation(%q) in remote file = %q, %v, want error", location, got, err)
+		}
+	}
+}
+
+func TestRegistry_RelativeVersionFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"lang_modules.json": `{
+			"go": {
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+				"library_to_module_versions": "versions/library.json",
+				"codegame_to_library_versions": "versions/codegame.json"
+			},
+			"js": {
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "js-module"},
+				"library_to_module_versions": {"client": "missing.json"},
+				"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+			}
+		}`,
+		"versions/library.json":  `{"client": "client.json"}`,
+		"versions/client.json":   `{"0.9": "0.4"}`,
+		"versions/codegame.json": `{"client": {"0.9": "0.9"}}`,
+	})
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry(filepath.Join(dir, "lang_modules.json"))
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	if m.libToModVersions["client"]["0.9"] != "0.4" {
+		t.Errorf("client library versions = %v, want nested file relative to versions/library.json", m.libToModVersions["client"])
+	}
+
+	_, err = registry.LoadModule("js")
+	if err == nil || !strings.Contains(err.Error(), "'js'") || !strings.Contains(err.Error(), "library_to_module_versions.client") {
+		t.Errorf("LoadModule error = %v, want error naming the language and field", err)
+	}
+}
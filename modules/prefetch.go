@@ -0,0 +1,175 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/code-game-project/cli-utils/cli"
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// PrefetchWorkers is the maximum number of languages Prefetch installs concurrently.
+var PrefetchWorkers = 4
+
+type PrefetchStatus string
+
+const (
+	PrefetchInstalled      PrefetchStatus = "installed"
+	PrefetchAlreadyPresent PrefetchStatus = "already_present"
+	PrefetchFailed         PrefetchStatus = "failed"
+)
+
+type PrefetchResult struct {
+	// empty if the module could not be loaded
+	ProjectType string
+	// nil if the module version could not be determined
+	Version versions.Version
+	Status  PrefetchStatus
+	Err     error
+}
+
+// Prefetch installs the module versions of each language in langs, which are needed to create and run projects of every
+// supported project type for cgVersion. Servers always use the latest module version.
+// If cgVersion is nil, the latest module versions are used.
+// Languages are processed concurrently by up to PrefetchWorkers workers.
+// The progress of all languages including their downloads is reported as one combined progress.
+// The returned map contains the results for every supported project type of each language.
+func (r *Registry) Prefetch(langs []string, cgVersion versions.Version) map[string][]PrefetchResult {
+	results := make(map[string][]PrefetchResult, len(langs))
+	if len(langs) == 0 {
+		return results
+	}
+
+	var mu sync.Mutex
+	progress := newPrefetchProgress(len(langs))
+	progress.report()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	workers := PrefetchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lang := range jobs {
+				langResults := r.prefetchLang(lang, cgVersion, progress.downloadFunc(lang))
+				mu.Lock()
+				results[lang] = langResults
+				mu.Unlock()
+				progress.done(lang)
+			}
+		}()
+	}
+	for _, lang := range langs {
+		jobs <- lang
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// Prefetch installs the modules of langs in DefaultRegistry needed for cgVersion (see Registry.Prefetch).
+func Prefetch(langs []string, cgVersion versions.Version) map[string][]PrefetchResult {
+	return DefaultRegistry.Prefetch(langs, cgVersion)
+}
+
+// prefetchProgress combines the progress of all languages of Prefetch. Each language counts as prefetchProgressUnit,
+// of which the language currently downloading gets the fraction downloaded so far.
+type prefetchProgress struct {
+	mu        sync.Mutex
+	langs     int64
+	doneLangs int64
+	// language -> progress of its current download in prefetchProgressUnit
+	downloads map[string]int64
+	message   string
+}
+
+const prefetchProgressUnit = 1000
+
+func newPrefetchProgress(langs int) *prefetchProgress {
+	return &prefetchProgress{
+		langs:     int64(langs),
+		downloads: make(map[string]int64),
+		message:   fmt.Sprintf("Installing modules for %d languages", langs),
+	}
+}
+
+// downloadFunc returns the function receiving the download progress of lang.
+func (p *prefetchProgress) downloadFunc(lang string) downloadProgressFunc {
+	return func(_ string, current, size int64) {
+		p.mu.Lock()
+		// a language is only complete when all of its downloads are done
+		p.downloads[lang] = current * (prefetchProgressUnit - 1) / size
+		p.mu.Unlock()
+		p.report()
+	}
+}
+
+func (p *prefetchProgress) done(lang string) {
+	p.mu.Lock()
+	delete(p.downloads, lang)
+	p.doneLangs++
+	p.mu.Unlock()
+	p.report()
+}
+
+func (p *prefetchProgress) report() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := p.doneLangs * prefetchProgressUnit
+	for _, d := range p.downloads {
+		current += d
+	}
+	feedback.Progress(FeedbackPkg, "prefetch", p.message, current, p.langs*prefetchProgressUnit, cli.UnitNone)
+}
+
+func (r *Registry) prefetchLang(lang string, cgVersion versions.Version, progress downloadProgressFunc) []PrefetchResult {
+	m, err := r.LoadModule(lang)
+	if err != nil {
+		return []PrefetchResult{{Status: PrefetchFailed, Err: fmt.Errorf("load module: %w", err)}}
+	}
+
+	results := make([]PrefetchResult, 0, 2)
+	installed := make(map[string]PrefetchStatus)
+	for _, projectType := range m.ProjectTypes() {
+		modVersion, _, err := m.projectVersions(projectType, cgVersion)
+		if errors.Is(err, ErrUnsupportedProjectType) {
+			continue
+		}
+		result := PrefetchResult{
+			ProjectType: projectType,
+			Version:     modVersion,
+		}
+		if err != nil {
+			result.Status = PrefetchFailed
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		if status, ok := installed[modVersion.String()]; ok {
+			result.Status = status
+			results = append(results, result)
+			continue
+		}
+		_, downloaded, err := m.installVersion(modVersion, false, progress)
+		switch {
+		case err != nil:
+			result.Status = PrefetchFailed
+			result.Err = fmt.Errorf("install module: %w", err)
+		case downloaded:
+			result.Status = PrefetchInstalled
+		default:
+			result.Status = PrefetchAlreadyPresent
+		}
+		installed[modVersion.String()] = result.Status
+		results = append(results, result)
+	}
+	return results
+}
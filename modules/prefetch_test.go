@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func TestPrefetch(t *testing.T) {
+	oldBinPath := moduleBinPath
+	moduleBinPath = t.TempDir()
+	defer func() {
+		moduleBinPath = oldBinPath
+	}()
+
+	layout := newOCITestLayout(t, "application/vnd.codegame.module.binary", []byte("module binary"), "v0.3.0", "v0.4.0")
+	registry := newTestRegistry(t, fmt.Sprintf(`{
+		"go": {
+			"source": {"provider": "oci", "layout": %q},
+			"library_to_module_versions": {"client": {"0.8": "0.3", "0.9": "0.4"}, "server": {"0.2": "0.3", "0.3": "0.4"}},
+			"codegame_to_library_versions": {"client": {"0.8": "0.8", "0.9": "0.9"}, "server": {"0.8": "0.2", "0.9": "0.3"}}
+		},
+		"js": {
+			"source": {"provider": "unknown"}
+		}
+	}`, layout.dir))
+
+	receiver := &recordingReceiver{}
+	feedback.Enable(receiver)
+	defer feedback.Disable()
+
+	results := registry.Prefetch([]string{"go", "js"}, versions.MustParse("0.9"))
+	goResults := results["go"]
+	if len(goResults) != 2 {
+		t.Fatalf("go results = %+v, want client and server", goResults)
+	}
+	for _, r := range goResults {
+		if r.Status != PrefetchInstalled || r.Version.String() != "0.4" || r.Err != nil {
+			t.Errorf("%s result = %+v, want installed 0.4", r.ProjectType, r)
+		}
+	}
+	if jsResults := results["js"]; len(jsResults) != 1 || jsResults[0].Status != PrefetchFailed || jsResults[0].ProjectType != "" {
+		t.Errorf("js results = %+v, want one failed result without project type", jsResults)
+	}
+
+	var downloadProgress, prefetchProgress bool
+	for _, e := range receiver.entries {
+		if !strings.HasPrefix(e, "progress modules prefetch Installing modules for 2 languages ") {
+			t.Errorf("unexpected feedback %q, want only the combined prefetch progress", e)
+		}
+		downloadProgress = downloadProgress || strings.Contains(e, " 999/2000 ") || strings.Contains(e, " 1999/2000 ")
+		prefetchProgress = prefetchProgress || strings.Contains(e, " 2000/2000 ")
+	}
+	if !downloadProgress || !prefetchProgress {
+		t.Errorf("progress = %v, want download progress and completion in the combined progress", receiver.entries)
+	}
+
+	results = registry.Prefetch([]string{"go"}, versions.MustParse("0.8"))
+	// the server module version is resolved from the CodeGame version as well
+	if r := results["go"]; len(r) != 2 || r[0].Status != PrefetchInstalled || r[0].Version.String() != "0.3" ||
+		r[1].Status != PrefetchInstalled || r[1].Version.String() != "0.3" {
+		t.Errorf("go results for 0.8 = %+v, want installed client and server 0.3", r)
+	}
+}
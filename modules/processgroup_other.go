@@ -0,0 +1,7 @@
+//go:build !unix
+
+package modules
+
+import "os/exec"
+
+func killProcessGroup(cmd *exec.Cmd) {}
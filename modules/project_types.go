@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Names of the project types built into the CLI.
+// Modules can support additional project types by adding them to the version maps in lang_modules.json.
+const (
+	ProjectTypeClient = "client"
+	ProjectTypeServer = "server"
+)
+
+var projectTypeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// ValidProjectTypeName returns true if name can be used as the name of a project type.
+func ValidProjectTypeName(name string) bool {
+	return projectTypeNameRegex.MatchString(name)
+}
+
+// ProjectTypeName returns the name of projectType as used in lang_modules.json and .codegame.json files.
+// customName is the projectTypeName field of the action data. It is only used for ProjectType_CUSTOM.
+func ProjectTypeName(projectType ProjectType, customName *string) string {
+	switch projectType {
+	case ProjectType_CLIENT:
+		return ProjectTypeClient
+	case ProjectType_SERVER:
+		return ProjectTypeServer
+	}
+	if customName == nil {
+		return ""
+	}
+	return *customName
+}
+
+// protoProjectType returns the representation of the project type name in action data.
+func protoProjectType(name string) (ProjectType, *string) {
+	switch name {
+	case ProjectTypeClient:
+		return ProjectType_CLIENT, nil
+	case ProjectTypeServer:
+		return ProjectType_SERVER, nil
+	}
+	return ProjectType_CUSTOM, &name
+}
+
+// checkProjectType returns an error wrapping ErrUnsupportedProjectType if the module executable with info does not support projectType.
+func checkProjectType(info ModuleInfo, projectType string) error {
+	supported := false
+	for _, t := range info.ProjectTypes {
+		if t == projectType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("%w: the module does not support '%s' projects", ErrUnsupportedProjectType, projectType)
+	}
+	if projectType != ProjectTypeClient && projectType != ProjectTypeServer && info.ProtocolVersion < 3 {
+		return fmt.Errorf("%w: the module does not support custom project types like '%s'", ErrUnsupportedProjectType, projectType)
+	}
+	return nil
+}
+
+func sortedProjectTypes[T any](m map[string]T) []string {
+	types := make([]string, 0, len(m))
+	for t := range m {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
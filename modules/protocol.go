@@ -0,0 +1,48 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+// ProtocolVersion is the version of the module protocol (info response and action data) implemented by this package.
+//
+//	0: modules without a 'protocol_version' field in their info response, which may already declare JSON action data
+//	1: protocol versions in the info response and action data
+//	2: action results, dry-run mode for create and update
+//	3: custom project types (ProjectType_CUSTOM with 'projectTypeName')
+const ProtocolVersion uint32 = 3
+
+var ErrUnsupportedProtocolVersion = errors.New("unsupported module protocol version")
+
+func checkModuleProtocolVersion(version uint32) error {
+	if version > ProtocolVersion {
+		return fmt.Errorf("%w: the module uses protocol version %d but the CLI only supports up to version %d, please update codegame-cli", ErrUnsupportedProtocolVersion, version, ProtocolVersion)
+	}
+	return nil
+}
+
+// setProtocolVersion sets the 'protocolVersion' field of actionData to the protocol version of the CLI.
+func setProtocolVersion(actionData proto.Message) {
+	message := actionData.ProtoReflect()
+	field := message.Descriptor().Fields().ByName("protocolVersion")
+	if field != nil {
+		message.Set(field, protoreflect.ValueOfUint32(ProtocolVersion))
+	}
+}
+
+// warnNewerProtocol is called by modules when they receive action data of a CLI with a newer protocol version.
+func warnNewerProtocol(actionData proto.Message) {
+	message, ok := actionData.(interface{ GetProtocolVersion() uint32 })
+	if !ok {
+		return
+	}
+	if message.GetProtocolVersion() > ProtocolVersion {
+		feedback.Warn(FeedbackPkg, "The CLI uses module protocol version %d but this module only understands version %d. Some features might not work as expected.", message.GetProtocolVersion(), ProtocolVersion)
+	}
+}
@@ -0,0 +1,93 @@
+package modules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/internal/testutil"
+)
+
+func Test_checkModuleProtocolVersion(t *testing.T) {
+	for _, version := range []uint32{0, ProtocolVersion} {
+		if err := checkModuleProtocolVersion(version); err != nil {
+			t.Errorf("checkModuleProtocolVersion(%d) = %s, want nil", version, err)
+		}
+	}
+	if err := checkModuleProtocolVersion(ProtocolVersion + 1); !errors.Is(err, ErrUnsupportedProtocolVersion) {
+		t.Errorf("checkModuleProtocolVersion(%d) = %v, want %s", ProtocolVersion+1, err, ErrUnsupportedProtocolVersion)
+	}
+}
+
+func Test_execInfo_jsonWithoutProtocolVersion(t *testing.T) {
+	t.Setenv("FAKEMODULE_PROTOCOL_VERSION", "0")
+	modulePath := testutil.BuildFakeModule(t)
+	info, err := execInfo(modulePath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ProtocolVersion != 0 || info.ActionDataFormat != ActionDataFormatJSON {
+		t.Fatalf("info = protocol %d, format %s, want protocol 0 with the declared json format", info.ProtocolVersion, info.ActionDataFormat)
+	}
+
+	dir := t.TempDir()
+	_, err = ExecAction(modulePath, info, ActionCreate, &ActionCreateData{
+		ProjectType: ProjectType_CLIENT,
+		Language:    "go",
+		GameName:    "test",
+	}, ExecOptions{Dir: dir})
+	if err != nil {
+		t.Fatalf("create: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.txt")); err != nil {
+		t.Errorf("create did not write main.txt: %s", err)
+	}
+}
+
+func Test_setProtocolVersion(t *testing.T) {
+	data := &ActionCreateData{Language: "go"}
+	setProtocolVersion(data)
+	if data.ProtocolVersion != ProtocolVersion {
+		t.Errorf("ProtocolVersion = %d, want %d", data.ProtocolVersion, ProtocolVersion)
+	}
+	if data.Language != "go" {
+		t.Errorf("Language = %s, want go", data.Language)
+	}
+
+	// must not panic for messages without a 'protocolVersion' field
+	setProtocolVersion(&ActionResult{})
+}
+
+func Test_warnNewerProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  uint32
+		wantWarn bool
+	}{
+		{"older", ProtocolVersion - 1, false},
+		{"same", ProtocolVersion, false},
+		{"newer", ProtocolVersion + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &recordingReceiver{}
+			feedback.Enable(receiver)
+			defer feedback.Disable()
+
+			warnNewerProtocol(&ActionCreateData{ProtocolVersion: tt.version})
+			if warned := len(receiver.entries) > 0; warned != tt.wantWarn {
+				t.Errorf("warned = %t, want %t: %v", warned, tt.wantWarn, receiver.entries)
+			}
+		})
+	}
+
+	receiver := &recordingReceiver{}
+	feedback.Enable(receiver)
+	defer feedback.Disable()
+	warnNewerProtocol(&ActionResult{})
+	if len(receiver.entries) > 0 {
+		t.Errorf("unexpected warning for message without protocol version: %v", receiver.entries)
+	}
+}
@@ -1,8 +1,13 @@
 package modules
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
+	"strings"
 
 	"github.com/code-game-project/cli-utils/versions"
 )
@@ -11,12 +16,180 @@ var ErrVersionNotFound = errors.New("version not found")
 
 var providers = map[string]provider{
 	"github": &ProviderGithub{},
+	"gitlab": &ProviderGitlab{},
+	"gitea":  &ProviderGitea{},
 	"local":  &ProviderLocal{},
+	"oci":    &ProviderOCI{},
 }
 
+// provider creates the module source of a language module from its 'source' object.
 type provider interface {
 	Name() string
-	ValidateProviderVars(providerVars map[string]any) (errs []string)
-	FindExactVersion(providerVars map[string]any, version versions.Version) (versions.Version, error)
-	DownloadModuleBinary(target io.Writer, providerVars map[string]any, version versions.Version) error
+	// newSource decodes providerVars (the 'source' object without the 'provider' field) into the typed source config of the provider.
+	// Errors about invalid fields are of type *SourceConfigError.
+	newSource(providerVars map[string]any) (Source, error)
+}
+
+// Source is a configured location of module binaries. It is also used for the binaries of CLI components.
+type Source interface {
+	FindExactVersion(version versions.Version) (versions.Version, error)
+	DownloadModuleBinary(target io.Writer, version versions.Version) error
+}
+
+// NewSource creates the source described by a 'source' object like in lang_modules.json.
+// Relative paths in source are resolved relative to base, which is the file path or URL of the file containing source.
+func NewSource(source map[string]any, base string) (Source, error) {
+	_, _, s, err := parseSource(source, base)
+	return s, err
+}
+
+// credentialFields are the fields of 'source' objects, which contain secrets.
+var credentialFields = []string{"token"}
+
+// PublicSource returns a copy of source without credentials, which can be written to files like install manifests.
+func PublicSource(source map[string]any) map[string]any {
+	public := make(map[string]any, len(source))
+	for k, v := range source {
+		public[k] = v
+	}
+	for _, f := range credentialFields {
+		delete(public, f)
+	}
+	return public
+}
+
+// parseSource returns the provider and the provider vars (source without 'provider') of source and creates the source.
+func parseSource(source map[string]any, base string) (provider, map[string]any, Source, error) {
+	providerNameAny, ok := source["provider"]
+	if !ok {
+		return nil, nil, nil, errors.New("missing 'source.provider' field")
+	}
+	providerName, ok := providerNameAny.(string)
+	if !ok {
+		return nil, nil, nil, errors.New("value of 'source.provider' field must be a string")
+	}
+
+	prov, ok := providers[providerName]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unknown module provider: %s", providerName)
+	}
+
+	providerVars := make(map[string]any, len(source))
+	for n, v := range source {
+		if n != "provider" {
+			providerVars[n] = v
+		}
+	}
+
+	if p, ok := prov.(pathProvider); ok {
+		err := resolvePathVars(providerVars, p.pathVars(), base)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	s, err := prov.newSource(providerVars)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid module source: %w", err)
+	}
+	return prov, providerVars, s, nil
+}
+
+// SourceConfigError is returned for invalid fields in the 'source' object of a language module.
+type SourceConfigError struct {
+	// name of the field in the 'source' object
+	Field string
+	Err   error
+}
+
+func (e *SourceConfigError) Error() string {
+	return fmt.Sprintf("field 'source.%s': %s", e.Field, e.Err)
+}
+
+func (e *SourceConfigError) Unwrap() error {
+	return e.Err
+}
+
+func missingField(field string) error {
+	return &SourceConfigError{Field: field, Err: errors.New("required field is missing")}
+}
+
+// decodeSourceConfig decodes providerVars into the source config struct pointed to by config.
+// Unknown fields are rejected. Fields of config which are not in providerVars keep their (default) values.
+func decodeSourceConfig(providerName string, providerVars map[string]any, config any) error {
+	data, err := json.Marshal(providerVars)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(config)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &SourceConfigError{Field: typeErr.Field, Err: fmt.Errorf("value must be %s", jsonTypeName(typeErr.Type))}
+	}
+	// encoding/json does not export an error type for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &SourceConfigError{Field: strings.Trim(field, `"`), Err: fmt.Errorf("unknown field for provider '%s'", providerName)}
+	}
+	return err
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list of " + strings.TrimPrefix(strings.TrimPrefix(jsonTypeName(t.Elem()), "a "), "an ") + "s"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return t.String()
+}
+
+// pathProvider is implemented by providers with provider vars containing local paths.
+// The paths are resolved relative to the config file containing the module.
+type pathProvider interface {
+	pathVars() []string
+}
+
+// resolvePathVars resolves the string or string list provider vars with the given names.
+func resolvePathVars(providerVars map[string]any, names []string, base string) error {
+	for _, name := range names {
+		switch value := providerVars[name].(type) {
+		case string:
+			path, err := resolveLocalPath(value, base)
+			if err != nil {
+				return fmt.Errorf("field 'source.%s': %w", name, err)
+			}
+			providerVars[name] = path
+		case []any:
+			paths := make([]any, len(value))
+			for i, v := range value {
+				str, ok := v.(string)
+				if !ok {
+					// reported by decodeSourceConfig
+					paths[i] = v
+					continue
+				}
+				path, err := resolveLocalPath(str, base)
+				if err != nil {
+					return fmt.Errorf("field 'source.%s[%d]': %w", name, i, err)
+				}
+				paths[i] = path
+			}
+			providerVars[name] = paths
+		}
+	}
+	return nil
 }
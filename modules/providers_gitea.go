@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/code-game-project/cli-utils/request"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+const giteaTagsPerPage = 50
+
+// ProviderGitea downloads module binaries from the release assets of a Gitea repository.
+type ProviderGitea struct{}
+
+func (p *ProviderGitea) Name() string {
+	return "gitea"
+}
+
+func (p *ProviderGitea) newSource(providerVars map[string]any) (Source, error) {
+	source := &GiteaSource{}
+	err := decodeSourceConfig(p.Name(), providerVars, source)
+	if err != nil {
+		return nil, err
+	}
+	if source.BaseURL == "" {
+		return nil, missingField("base_url")
+	}
+	if source.Owner == "" {
+		return nil, missingField("owner")
+	}
+	if source.Repository == "" {
+		return nil, missingField("repository")
+	}
+	return source, nil
+}
+
+// GiteaSource is the source config of the 'gitea' provider.
+type GiteaSource struct {
+	// URL of the Gitea instance
+	BaseURL    string `json:"base_url"`
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	// access token
+	Token string `json:"token"`
+}
+
+func (s *GiteaSource) FindExactVersion(version versions.Version) (versions.Version, error) {
+	_, tagVersion, err := s.findTag(version)
+	return tagVersion, err
+}
+
+func (s *GiteaSource) DownloadModuleBinary(target io.Writer, version versions.Version) error {
+	tag, _, err := s.findTag(version)
+	if err != nil {
+		return err
+	}
+
+	type response struct {
+		Assets []struct {
+			Name               string `json:"name"`
+			Size               int64  `json:"size"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	release, err := request.FetchJSONWithHeader[response](s.repoURL("releases", "tags", url.PathEscape(tag)), s.header(), 0)
+	if err != nil {
+		return fmt.Errorf("fetch Gitea release: %w", err)
+	}
+
+	repository := s.Repository
+	assetName := releaseAssetName(repository)
+	for _, asset := range release.Assets {
+		if asset.Name == assetName {
+			return downloadReleaseAsset(target, releaseAsset{URL: asset.BrowserDownloadURL, Size: asset.Size}, headerForHost(s.header(), s.BaseURL, asset.BrowserDownloadURL), repository)
+		}
+	}
+	return fmt.Errorf("release asset '%s': %w", assetName, ErrFileNotFound)
+}
+
+func (s *GiteaSource) findTag(version versions.Version) (string, versions.Version, error) {
+	type tag struct {
+		Name string `json:"name"`
+	}
+	tags, err := fetchPaginated[tag](func(page int) string {
+		return s.repoURL(fmt.Sprintf("tags?page=%d&limit=%d", page, giteaTagsPerPage))
+	}, s.header())
+	if err != nil {
+		return "", nil, fmt.Errorf("list Gitea tags: %w", err)
+	}
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return latestTag(names, version)
+}
+
+func (s *GiteaSource) repoURL(elem ...string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s/%s", strings.TrimSuffix(s.BaseURL, "/"), url.PathEscape(s.Owner), url.PathEscape(s.Repository), strings.Join(elem, "/"))
+}
+
+func (s *GiteaSource) header() http.Header {
+	header := make(http.Header)
+	if s.Token != "" {
+		header.Set("Authorization", "token "+s.Token)
+	}
+	return header
+}
@@ -9,12 +9,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"runtime"
 	"strings"
-	"time"
 
-	"github.com/code-game-project/cli-utils/cli"
-	"github.com/code-game-project/cli-utils/feedback"
 	"github.com/code-game-project/cli-utils/request"
 	"github.com/code-game-project/cli-utils/versions"
 )
@@ -27,23 +23,34 @@ func (p *ProviderGithub) Name() string {
 	return "github"
 }
 
-func (p *ProviderGithub) ValidateProviderVars(providerVars map[string]any) []string {
-	var errs []string
-	if _, ok := providerVars["owner"]; !ok {
-		errs = append(errs, "missing 'owner' field")
-	} else if _, ok := providerVars["owner"].(string); !ok {
-		errs = append(errs, "value of 'owner' field must be a string")
+func (p *ProviderGithub) newSource(providerVars map[string]any) (Source, error) {
+	source := &GithubSource{
+		BaseURL: "https://api.github.com",
 	}
-	if _, ok := providerVars["repository"]; !ok {
-		errs = append(errs, "missing 'repository' field")
-	} else if _, ok := providerVars["repository"].(string); !ok {
-		errs = append(errs, "value of 'repository' field must be a string")
+	err := decodeSourceConfig(p.Name(), providerVars, source)
+	if err != nil {
+		return nil, err
+	}
+	if source.Owner == "" {
+		return nil, missingField("owner")
 	}
-	return errs
+	if source.Repository == "" {
+		return nil, missingField("repository")
+	}
+	source.BaseURL = strings.TrimSuffix(source.BaseURL, "/")
+	return source, nil
+}
+
+// GithubSource is the source config of the 'github' provider.
+type GithubSource struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	// URL of the REST API (default: https://api.github.com), https://<host>/api/v3 for GitHub Enterprise Server
+	BaseURL string `json:"base_url"`
 }
 
-func (p *ProviderGithub) FindExactVersion(providerVars map[string]any, version versions.Version) (versions.Version, error) {
-	tag, err := p.findTagByVersion(providerVars["owner"].(string), providerVars["repository"].(string), version)
+func (s *GithubSource) FindExactVersion(version versions.Version) (versions.Version, error) {
+	tag, err := s.findTagByVersion(version)
 	if err != nil {
 		return nil, err
 	}
@@ -54,40 +61,21 @@ func (p *ProviderGithub) FindExactVersion(providerVars map[string]any, version v
 	return tagVersion, nil
 }
 
-func (p *ProviderGithub) DownloadModuleBinary(target io.Writer, providerVars map[string]any, version versions.Version) error {
-	downloadFileName := fmt.Sprintf("%s-%s-%s.tar.gz", providerVars["repository"], runtime.GOOS, runtime.GOARCH)
-	if runtime.GOOS == "windows" {
-		downloadFileName = fmt.Sprintf("%s-%s-%s.zip", providerVars["repository"], runtime.GOOS, runtime.GOARCH)
-	}
-
-	feedback.InterceptProgress(request.FeedbackPkg, func(_ feedback.Package, _, _ string, current, total int64, unit cli.Unit) {
-		feedback.Progress(FeedbackPkg, fmt.Sprintf("download %s", providerVars["repository"]), fmt.Sprintf("Downloading module %s", providerVars["repository"]), current, total, unit)
-	})
-	file, err := request.FetchFile(fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", providerVars["owner"], providerVars["repository"], fmt.Sprintf("v%s", version), downloadFileName), 0, true)
-	defer feedback.UninterceptProgress(request.FeedbackPkg)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return err
-	}
-	defer file.Close()
-
-	fileName := providerVars["repository"].(string)
-
-	if runtime.GOOS == "windows" {
-		err = unzipFile(file, fileName+".exe", target)
-	} else {
-		err = untargzFile(file, fileName, target)
-	}
+// DownloadModuleBinary downloads the release asset for the current platform and verifies it with the size and digest
+// in the release metadata.
+func (s *GithubSource) DownloadModuleBinary(target io.Writer, version versions.Version) error {
+	asset, err := s.findReleaseAsset(fmt.Sprintf("v%s", version))
 	if err != nil {
 		return err
 	}
-	return nil
+	return downloadReleaseAsset(target, asset, nil, s.Repository)
 }
 
-func (p *ProviderGithub) findTagByVersion(owner, repo string, version versions.Version) (string, error) {
+func (s *GithubSource) findTagByVersion(version versions.Version) (string, error) {
 	type response []struct {
 		Name string `json:"name"`
 	}
-	res, err := request.FetchJSON[response](fmt.Sprintf("https://api.github.com/repos/%s/%s/tags", owner, repo), 24*time.Hour)
+	res, err := request.FetchJSON[response](fmt.Sprintf("%s/repos/%s/%s/tags", s.BaseURL, s.Owner, s.Repository), releaseTagsCacheMaxAge)
 	if err != nil {
 		return "", fmt.Errorf("find GitHub tag by version: %w", err)
 	}
@@ -99,6 +87,29 @@ func (p *ProviderGithub) findTagByVersion(owner, repo string, version versions.V
 	return "", ErrVersionNotFound
 }
 
+func (s *GithubSource) findReleaseAsset(tag string) (releaseAsset, error) {
+	type response struct {
+		Assets []struct {
+			Name string `json:"name"`
+			Size int64  `json:"size"`
+			// empty for releases published before GitHub started to record asset digests
+			Digest             string `json:"digest"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	res, err := request.FetchJSON[response](fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", s.BaseURL, s.Owner, s.Repository, tag), releaseTagsCacheMaxAge)
+	if err != nil {
+		return releaseAsset{}, fmt.Errorf("fetch GitHub release: %w", err)
+	}
+	assetName := releaseAssetName(s.Repository)
+	for _, asset := range res.Assets {
+		if asset.Name == assetName {
+			return releaseAsset{URL: asset.BrowserDownloadURL, Size: asset.Size, Digest: asset.Digest}, nil
+		}
+	}
+	return releaseAsset{}, fmt.Errorf("release %s has no asset named '%s'", tag, assetName)
+}
+
 // untargzFile first decompresses source with gzip, then extracts the file with fileName into outputFileName.
 func untargzFile(source io.Reader, fileName string, target io.Writer) error {
 	archive, err := gzip.NewReader(source)
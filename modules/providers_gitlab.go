@@ -0,0 +1,118 @@
+package modules
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/code-game-project/cli-utils/request"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+const gitlabTagsPerPage = 100
+
+// ProviderGitlab downloads module binaries from the release assets of a GitLab project.
+type ProviderGitlab struct{}
+
+func (p *ProviderGitlab) Name() string {
+	return "gitlab"
+}
+
+func (p *ProviderGitlab) newSource(providerVars map[string]any) (Source, error) {
+	source := &GitlabSource{
+		BaseURL: "https://gitlab.com",
+	}
+	err := decodeSourceConfig(p.Name(), providerVars, source)
+	if err != nil {
+		return nil, err
+	}
+	if source.Project == "" {
+		return nil, missingField("project")
+	}
+	if source.BaseURL == "" {
+		return nil, missingField("base_url")
+	}
+	return source, nil
+}
+
+// GitlabSource is the source config of the 'gitlab' provider.
+type GitlabSource struct {
+	// full path of the project (e.g. "group/subgroup/module")
+	Project string `json:"project"`
+	// URL of the GitLab instance (default: https://gitlab.com)
+	BaseURL string `json:"base_url"`
+	// personal, project or group access token
+	Token string `json:"token"`
+}
+
+func (s *GitlabSource) FindExactVersion(version versions.Version) (versions.Version, error) {
+	_, tagVersion, err := s.findTag(version)
+	return tagVersion, err
+}
+
+func (s *GitlabSource) DownloadModuleBinary(target io.Writer, version versions.Version) error {
+	tag, _, err := s.findTag(version)
+	if err != nil {
+		return err
+	}
+
+	type response struct {
+		Assets struct {
+			Links []struct {
+				Name           string `json:"name"`
+				URL            string `json:"url"`
+				DirectAssetURL string `json:"direct_asset_url"`
+			} `json:"links"`
+		} `json:"assets"`
+	}
+	release, err := request.FetchJSONWithHeader[response](s.projectURL("releases", url.PathEscape(tag)), s.header(), 0)
+	if err != nil {
+		return fmt.Errorf("fetch GitLab release: %w", err)
+	}
+
+	repository := path.Base(s.Project)
+	assetName := releaseAssetName(repository)
+	for _, link := range release.Assets.Links {
+		if link.Name != assetName {
+			continue
+		}
+		assetURL := link.DirectAssetURL
+		if assetURL == "" {
+			assetURL = link.URL
+		}
+		return downloadReleaseAsset(target, releaseAsset{URL: assetURL, Size: -1}, headerForHost(s.header(), s.BaseURL, assetURL), repository)
+	}
+	return fmt.Errorf("release asset '%s': %w", assetName, ErrFileNotFound)
+}
+
+func (s *GitlabSource) findTag(version versions.Version) (string, versions.Version, error) {
+	type tag struct {
+		Name string `json:"name"`
+	}
+	tags, err := fetchPaginated[tag](func(page int) string {
+		return s.projectURL("repository", fmt.Sprintf("tags?per_page=%d&page=%d", gitlabTagsPerPage, page))
+	}, s.header())
+	if err != nil {
+		return "", nil, fmt.Errorf("list GitLab tags: %w", err)
+	}
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return latestTag(names, version)
+}
+
+func (s *GitlabSource) projectURL(elem ...string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/%s", strings.TrimSuffix(s.BaseURL, "/"), url.PathEscape(s.Project), strings.Join(elem, "/"))
+}
+
+func (s *GitlabSource) header() http.Header {
+	header := make(http.Header)
+	if s.Token != "" {
+		header.Set("PRIVATE-TOKEN", s.Token)
+	}
+	return header
+}
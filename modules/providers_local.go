@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -13,78 +14,76 @@ func (p *ProviderLocal) Name() string {
 	return "local"
 }
 
-func (p *ProviderLocal) ValidateProviderVars(providerVars map[string]any) []string {
-	var errs []string
-	_, containsPath := providerVars["path"]
-	_, containsPaths := providerVars["paths"]
-	if !containsPath && !containsPaths {
-		errs = append(errs, "missing 'path' or 'paths' field")
-	}
-	if containsPath {
-		if _, ok := providerVars["path"].(string); !ok {
-			errs = append(errs, "value of 'path' field must be a string")
-		}
+func (p *ProviderLocal) newSource(providerVars map[string]any) (Source, error) {
+	source := &LocalSource{}
+	err := decodeSourceConfig(p.Name(), providerVars, source)
+	if err != nil {
+		return nil, err
 	}
-	if containsPaths {
-		if list, ok := providerVars["paths"].([]any); ok {
-			for _, p := range list {
-				if _, ok := p.(string); !ok {
-					errs = append(errs, "value of 'paths' field must be a string list")
-					break
-				}
-			}
-		} else {
-			errs = append(errs, "value of 'paths' field must be a string list")
-		}
+	if source.Path == "" && len(source.Paths) == 0 {
+		return nil, &SourceConfigError{Field: "path", Err: errors.New("either 'path' or 'paths' is required")}
 	}
-	return errs
+	return source, nil
+}
+
+func (p *ProviderLocal) pathVars() []string {
+	return []string{"path", "paths"}
+}
+
+// LocalSource is the source config of the 'local' provider.
+type LocalSource struct {
+	// path of a module executable
+	Path string `json:"path"`
+	// paths of module executables
+	Paths []string `json:"paths"`
 }
 
-func (p *ProviderLocal) FindExactVersion(providerVars map[string]any, version versions.Version) (versions.Version, error) {
+func (s *LocalSource) FindExactVersion(version versions.Version) (versions.Version, error) {
 	return version, nil
 }
 
-func (p *ProviderLocal) DownloadModuleBinary(target io.Writer, providerVars map[string]any, version versions.Version) error {
-	panic("cannot download local modules")
+func (s *LocalSource) DownloadModuleBinary(target io.Writer, version versions.Version) error {
+	return errors.New("cannot download local modules")
 }
 
 func (m *Module) loadLocalModules() error {
-	path, ok := m.providerVars["path"]
-	if ok {
-		err := m.loadLocalModulePath(path.(string))
+	source, ok := m.source.(*LocalSource)
+	if !ok {
+		return errors.New("not a local module")
+	}
+	paths := source.Paths
+	if source.Path != "" {
+		paths = append([]string{source.Path}, paths...)
+	}
+	for _, p := range paths {
+		err := m.loadLocalModulePath(p)
 		if err != nil {
 			return err
 		}
 	}
-	rawPaths, ok := m.providerVars["paths"]
-	if ok {
-		for _, p := range rawPaths.([]any) {
-			err := m.loadLocalModulePath(p.(string))
-			if err != nil {
-				return err
-			}
-		}
-	}
 	return nil
 }
 
 func (m *Module) loadLocalModulePath(path string) error {
-	info, err := execInfo(path)
+	info, err := m.info(path)
 	if err != nil {
 		return fmt.Errorf("receive module version of '%s': %w", path, err)
 	}
 
 	version := info.Version.String()
-	for _, v := range info.LibraryVersions["client"] {
-		if _, ok := m.clientLibToModVersions[v.String()]; !ok {
-			m.clientLibToModVersions[v.String()] = version
+	for projectType, libVersions := range info.LibraryVersions {
+		versionMap := m.libToModVersions[projectType]
+		if versionMap == nil {
+			versionMap = make(map[string]string)
+			m.libToModVersions[projectType] = versionMap
 		}
-	}
-	for _, v := range info.LibraryVersions["server"] {
-		if _, ok := m.serverLibToModVersions[v.String()]; !ok {
-			m.serverLibToModVersions[v.String()] = version
+		for _, v := range libVersions {
+			if _, ok := versionMap[v.String()]; !ok {
+				versionMap[v.String()] = version
+			}
 		}
 	}
 	m.installedExecutables[version] = path
+	m.infos[path] = info
 	return nil
 }
@@ -0,0 +1,643 @@
+package modules
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+const (
+	ociMediaTypeIndex           = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeManifest        = "application/vnd.oci.image.manifest.v1+json"
+	dockerMediaTypeManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	dockerMediaTypeManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+
+	ociAnnotationRefName = "org.opencontainers.image.ref.name"
+	ociAnnotationTitle   = "org.opencontainers.image.title"
+)
+
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// ProviderOCI downloads module binaries from OCI artifacts.
+// Module versions map to tags. The artifact for the current platform is selected from the image index.
+type ProviderOCI struct{}
+
+func (p *ProviderOCI) Name() string {
+	return "oci"
+}
+
+func (p *ProviderOCI) newSource(providerVars map[string]any) (Source, error) {
+	source := &OCISource{}
+	err := decodeSourceConfig(p.Name(), providerVars, source)
+	if err != nil {
+		return nil, err
+	}
+	if (source.Layout == "") == (source.Registry == "") {
+		return nil, &SourceConfigError{Field: "layout", Err: errors.New("exactly one of 'layout' and 'registry' is required")}
+	}
+	if source.Registry != "" && source.Repository == "" {
+		return nil, missingField("repository")
+	}
+	return source, nil
+}
+
+func (p *ProviderOCI) pathVars() []string {
+	return []string{"layout"}
+}
+
+// OCISource is the source config of the 'oci' provider.
+type OCISource struct {
+	// path of an OCI image layout directory
+	Layout string `json:"layout"`
+	// base URL of an OCI distribution registry
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	// Bearer token for the registry. If the registry requests a token exchange, it is used as the password
+	// for the token service instead (e.g. a personal access token).
+	Token string `json:"token"`
+	// user name for the token service, default: "token"
+	Username string `json:"username"`
+	// name of the module binary in the artifact (required for archive layers)
+	File string `json:"file"`
+}
+
+func (s *OCISource) FindExactVersion(version versions.Version) (versions.Version, error) {
+	_, tagVersion, err := findOCITag(s.store(), version)
+	return tagVersion, err
+}
+
+func (s *OCISource) DownloadModuleBinary(target io.Writer, version versions.Version) error {
+	store := s.store()
+	tag, _, err := findOCITag(store, version)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := resolveOCIManifest(store, tag)
+	if err != nil {
+		return err
+	}
+
+	fileName := s.File
+	if fileName != "" && runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+	layer, err := selectOCILayer(manifest.Layers, fileName)
+	if err != nil {
+		return err
+	}
+
+	name := fileName
+	if name == "" {
+		name = tag
+	}
+	blob, err := store.blob(layer)
+	if err != nil {
+		return fmt.Errorf("fetch layer %s: %w", layer.Digest, err)
+	}
+	defer blob.Close()
+
+	verifier, err := newDigestReader(newDownloadProgressReader(blob, target, layer.Digest, name, layer.Size), layer)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasSuffix(layer.MediaType, "tar+gzip") || strings.HasSuffix(layer.MediaType, "tar.gzip"):
+		if fileName == "" {
+			return errors.New("the 'file' field is required for archive layers")
+		}
+		err = untargzFile(verifier, fileName, target)
+	case strings.HasSuffix(layer.MediaType, "tar"):
+		if fileName == "" {
+			return errors.New("the 'file' field is required for archive layers")
+		}
+		err = untarFile(verifier, fileName, target)
+	default:
+		_, err = io.Copy(target, verifier)
+	}
+	if err != nil {
+		return err
+	}
+	return verifier.verify()
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	} `json:"platform,omitempty"`
+}
+
+type ociIndex struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociStore interface {
+	tags() ([]string, error)
+	// manifest returns the raw manifest or image index referenced by a tag or digest.
+	manifest(reference string) ([]byte, error)
+	blob(descriptor ociDescriptor) (io.ReadCloser, error)
+}
+
+func (s *OCISource) store() ociStore {
+	if s.Layout != "" {
+		return &ociLayoutStore{dir: s.Layout}
+	}
+	registry := s.Registry
+	if !strings.HasPrefix(registry, "http://") && !strings.HasPrefix(registry, "https://") {
+		registry = "https://" + registry
+	}
+	return &ociRegistryStore{
+		baseURL:    strings.TrimSuffix(registry, "/"),
+		repository: s.Repository,
+		username:   s.Username,
+		password:   s.Token,
+		token:      s.Token,
+	}
+}
+
+// findOCITag returns the tag with the latest version compatible with version.
+func findOCITag(store ociStore, version versions.Version) (string, versions.Version, error) {
+	tags, err := store.tags()
+	if err != nil {
+		return "", nil, fmt.Errorf("list OCI tags: %w", err)
+	}
+	return latestTag(tags, version)
+}
+
+// resolveOCIManifest returns the manifest referenced by reference for the current platform.
+func resolveOCIManifest(store ociStore, reference string) (ociManifest, error) {
+	// image indexes may be nested
+	for depth := 0; depth < 4; depth++ {
+		data, err := store.manifest(reference)
+		if err != nil {
+			return ociManifest{}, fmt.Errorf("fetch manifest %s: %w", reference, err)
+		}
+
+		var index ociIndex
+		err = json.Unmarshal(data, &index)
+		if err != nil {
+			return ociManifest{}, fmt.Errorf("decode manifest %s: %w", reference, err)
+		}
+		isIndex := index.MediaType == ociMediaTypeIndex || index.MediaType == dockerMediaTypeManifestList || (index.MediaType == "" && index.Manifests != nil)
+		if !isIndex {
+			var manifest ociManifest
+			err = json.Unmarshal(data, &manifest)
+			if err != nil {
+				return ociManifest{}, fmt.Errorf("decode manifest %s: %w", reference, err)
+			}
+			return manifest, nil
+		}
+
+		descriptor, ok := selectOCIPlatform(index.Manifests)
+		if !ok {
+			return ociManifest{}, fmt.Errorf("no manifest for %s/%s in image index %s", runtime.GOOS, runtime.GOARCH, reference)
+		}
+		reference = descriptor.Digest
+	}
+	return ociManifest{}, errors.New("too many nested image indexes")
+}
+
+func selectOCIPlatform(manifests []ociDescriptor) (ociDescriptor, bool) {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+			return m, true
+		}
+	}
+	// platform independent artifact
+	if len(manifests) == 1 && manifests[0].Platform == nil {
+		return manifests[0], true
+	}
+	return ociDescriptor{}, false
+}
+
+// selectOCILayer returns the layer titled fileName or the only layer if there is no such layer.
+func selectOCILayer(layers []ociDescriptor, fileName string) (ociDescriptor, error) {
+	if fileName != "" {
+		for _, l := range layers {
+			if l.Annotations[ociAnnotationTitle] == fileName {
+				return l, nil
+			}
+		}
+	}
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+	return ociDescriptor{}, fmt.Errorf("cannot determine module layer: %w", ErrFileNotFound)
+}
+
+type ociLayoutStore struct {
+	dir string
+}
+
+func (s *ociLayoutStore) index() (ociIndex, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "index.json"))
+	if err != nil {
+		return ociIndex{}, err
+	}
+	var index ociIndex
+	err = json.Unmarshal(data, &index)
+	if err != nil {
+		return ociIndex{}, fmt.Errorf("decode index.json: %w", err)
+	}
+	return index, nil
+}
+
+func (s *ociLayoutStore) tags() ([]string, error) {
+	index, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(index.Manifests))
+	for _, m := range index.Manifests {
+		if name, ok := m.Annotations[ociAnnotationRefName]; ok {
+			tags = append(tags, name)
+		}
+	}
+	return tags, nil
+}
+
+func (s *ociLayoutStore) manifest(reference string) ([]byte, error) {
+	descriptor := ociDescriptor{Digest: reference, Size: -1}
+	if !strings.Contains(reference, ":") {
+		index, err := s.index()
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, m := range index.Manifests {
+			if m.Annotations[ociAnnotationRefName] == reference {
+				descriptor = m
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrVersionNotFound
+		}
+	}
+
+	blob, err := s.blob(descriptor)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	return readVerified(blob, descriptor)
+}
+
+func (s *ociLayoutStore) blob(descriptor ociDescriptor) (io.ReadCloser, error) {
+	algorithm, encoded, ok := strings.Cut(descriptor.Digest, ":")
+	if !ok || strings.ContainsAny(encoded, `/\.`) || strings.ContainsAny(algorithm, `/\.`) {
+		return nil, fmt.Errorf("invalid digest: %s", descriptor.Digest)
+	}
+	return os.Open(filepath.Join(s.dir, "blobs", algorithm, encoded))
+}
+
+// ociRegistryStore accesses a registry implementing the OCI distribution spec.
+// Tokens are requested from the token service of the registry when it responds with a bearer challenge,
+// which most registries do even for anonymous pulls.
+type ociRegistryStore struct {
+	baseURL    string
+	repository string
+	// credentials for the token service
+	username string
+	password string
+	// bearer token sent to the registry
+	token string
+}
+
+// maximum number of tags requested per page
+const ociTagsPerPage = 1000
+
+func (s *ociRegistryStore) fetch(url string, accept []string) (*http.Response, error) {
+	resp, err := s.get(url, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return nil, fmt.Errorf("http status: %s", http.StatusText(http.StatusUnauthorized))
+		}
+		s.token, err = s.requestToken(challenge)
+		if err != nil {
+			return nil, fmt.Errorf("request registry token: %w", err)
+		}
+		resp, err = s.get(url, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrVersionNotFound
+		}
+		return nil, fmt.Errorf("http status: %s", http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+func (s *ociRegistryStore) get(url string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create http request: %w", err)
+	}
+	for _, a := range accept {
+		req.Header.Add("Accept", a)
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return ociHTTPClient.Do(req)
+}
+
+// ociHTTPClient does not send the credentials for the registry to other hosts, e.g. storage services serving blobs.
+var ociHTTPClient = &http.Client{
+	CheckRedirect: func(r *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if r.URL.Host != via[0].URL.Host {
+			r.Header.Del("Authorization")
+		}
+		return nil
+	},
+}
+
+// requestToken requests a token for pulling from the repository from the token service in challenge
+// (a WWW-Authenticate header value like 'Bearer realm="https://example.com/token",service="example.com"').
+func (s *ociRegistryStore) requestToken(challenge string) (string, error) {
+	params := parseAuthParams(challenge[len("bearer "):])
+	realm, err := url.Parse(params["realm"])
+	if err != nil || (realm.Scheme != "https" && realm.Scheme != "http") {
+		return "", fmt.Errorf("invalid realm in challenge: %s", challenge)
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope, ok := params["scope"]
+	if !ok {
+		scope = "repository:" + s.repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("create http request: %w", err)
+	}
+	if s.password != "" {
+		username := s.username
+		if username == "" {
+			username = "token"
+		}
+		req.SetBasicAuth(username, s.password)
+	}
+	resp, err := ociHTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("http status: %s", http.StatusText(resp.StatusCode))
+	}
+	var response struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+	if response.Token != "" {
+		return response.Token, nil
+	}
+	if response.AccessToken != "" {
+		return response.AccessToken, nil
+	}
+	return "", errors.New("empty token response")
+}
+
+// parseAuthParams parses the comma-separated key=value or key="value" parameters of a challenge.
+func parseAuthParams(params string) map[string]string {
+	result := make(map[string]string)
+	for len(params) > 0 {
+		params = strings.TrimLeft(params, " ,")
+		key, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				break
+			}
+			value, params = rest[1:end+1], rest[end+2:]
+		} else {
+			value, params, _ = strings.Cut(rest, ",")
+		}
+		result[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return result
+}
+
+func (s *ociRegistryStore) url(a ...string) string {
+	return fmt.Sprintf("%s/v2/%s/%s", s.baseURL, s.repository, path.Join(a...))
+}
+
+// tags returns all tags of the repository. Following the 'Link' header, all pages of the tag list are requested.
+func (s *ociRegistryStore) tags() ([]string, error) {
+	var tags []string
+	next := fmt.Sprintf("%s?n=%d", s.url("tags", "list"), ociTagsPerPage)
+	for page := 0; next != ""; page++ {
+		if page >= maxPages {
+			return nil, fmt.Errorf("more than %d pages", maxPages)
+		}
+		resp, err := s.fetch(next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var response struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode tag list: %w", err)
+		}
+		tags = append(tags, response.Tags...)
+
+		next, err = nextLink(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// nextLink returns the absolute URL of the 'next' link in the 'Link' header of resp or "" if there is none.
+func nextLink(resp *http.Response) (string, error) {
+	for _, link := range resp.Header.Values("Link") {
+		for _, l := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(l), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			if parseAuthParams(strings.ReplaceAll(params, ";", ","))["rel"] != "next" {
+				continue
+			}
+			ref, err := url.Parse(target[1 : len(target)-1])
+			if err != nil {
+				return "", fmt.Errorf("invalid link: %s", l)
+			}
+			return resp.Request.URL.ResolveReference(ref).String(), nil
+		}
+	}
+	return "", nil
+}
+
+func (s *ociRegistryStore) manifest(reference string) ([]byte, error) {
+	resp, err := s.fetch(s.url("manifests", reference), []string{ociMediaTypeIndex, ociMediaTypeManifest, dockerMediaTypeManifestList, dockerMediaTypeManifest})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	digest := reference
+	if !strings.Contains(reference, ":") {
+		// the registry returns the digest of manifests referenced by tag
+		digest = resp.Header.Get("Docker-Content-Digest")
+		if digest == "" {
+			return nil, fmt.Errorf("the registry did not return the digest of the manifest %s", reference)
+		}
+	}
+	return readVerified(resp.Body, ociDescriptor{Digest: digest, Size: -1})
+}
+
+func (s *ociRegistryStore) blob(descriptor ociDescriptor) (io.ReadCloser, error) {
+	resp, err := s.fetch(s.url("blobs", descriptor.Digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// digestReader verifies the size and digest of the content of a descriptor.
+// A digestReader without hash only verifies the size.
+type digestReader struct {
+	r         io.Reader
+	hash      hash.Hash
+	digest    string
+	size      int64
+	bytesRead int64
+	algorithm string
+}
+
+func newDigestReader(r io.Reader, descriptor ociDescriptor) (*digestReader, error) {
+	algorithm, encoded, ok := strings.Cut(descriptor.Digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid digest: %s", descriptor.Digest)
+	}
+	var h hash.Hash
+	switch algorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm: %s", algorithm)
+	}
+	return &digestReader{
+		r:         io.TeeReader(r, h),
+		hash:      h,
+		digest:    encoded,
+		size:      descriptor.Size,
+		algorithm: algorithm,
+	}, nil
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.bytesRead += int64(n)
+	return n, err
+}
+
+// verify reads the remaining content and compares its size and digest with the descriptor.
+func (d *digestReader) verify() error {
+	_, err := io.Copy(io.Discard, d)
+	if err != nil {
+		return err
+	}
+	if d.size >= 0 && d.bytesRead != d.size {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrDigestMismatch, d.size, d.bytesRead)
+	}
+	if d.hash == nil {
+		return nil
+	}
+	actual := hex.EncodeToString(d.hash.Sum(nil))
+	if actual != d.digest {
+		return fmt.Errorf("%w: expected %s:%s, got %s:%s", ErrDigestMismatch, d.algorithm, d.digest, d.algorithm, actual)
+	}
+	return nil
+}
+
+func readVerified(r io.Reader, descriptor ociDescriptor) ([]byte, error) {
+	verifier, err := newDigestReader(r, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(verifier)
+	if err != nil {
+		return nil, err
+	}
+	return data, verifier.verify()
+}
+
+// untarFile extracts the file with fileName from the tar archive source into target.
+func untarFile(source io.Reader, fileName string, target io.Writer) error {
+	tarReader := tar.NewReader(source)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		info := header.FileInfo()
+		if !info.IsDir() && info.Name() == fileName {
+			_, err = io.Copy(target, tarReader)
+			return err
+		}
+	}
+	return ErrFileNotFound
+}
@@ -0,0 +1,307 @@
+package modules
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// ociTestLayout is an OCI image layout with module artifacts for the current and another platform.
+type ociTestLayout struct {
+	dir   string
+	blobs map[string][]byte // digest -> content
+}
+
+func newOCITestLayout(t *testing.T, layerMediaType string, layer []byte, tags ...string) *ociTestLayout {
+	t.Helper()
+	l := &ociTestLayout{
+		dir:   t.TempDir(),
+		blobs: make(map[string][]byte),
+	}
+
+	layerDesc := l.addBlob(t, layerMediaType, layer)
+	layerDesc.Annotations = map[string]string{ociAnnotationTitle: "module"}
+	manifestDesc := l.addJSONBlob(t, ociMediaTypeManifest, ociManifest{
+		MediaType: ociMediaTypeManifest,
+		Layers:    []ociDescriptor{layerDesc},
+	})
+	otherDesc := l.addJSONBlob(t, ociMediaTypeManifest, ociManifest{
+		MediaType: ociMediaTypeManifest,
+		Layers:    []ociDescriptor{l.addBlob(t, layerMediaType, []byte("wrong platform"))},
+	})
+	manifestDesc.Platform = &struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	}{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+	otherDesc.Platform = &struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	}{OS: "plan9", Architecture: "mips"}
+	indexDesc := l.addJSONBlob(t, ociMediaTypeIndex, ociIndex{
+		MediaType: ociMediaTypeIndex,
+		Manifests: []ociDescriptor{otherDesc, manifestDesc},
+	})
+
+	root := ociIndex{MediaType: ociMediaTypeIndex}
+	for _, tag := range tags {
+		desc := indexDesc
+		desc.Annotations = map[string]string{ociAnnotationRefName: tag}
+		root.Manifests = append(root.Manifests, desc)
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(l.dir, "index.json"), data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func (l *ociTestLayout) addBlob(t *testing.T, mediaType string, data []byte) ociDescriptor {
+	t.Helper()
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	err := os.MkdirAll(filepath.Join(l.dir, "blobs", "sha256"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(l.dir, "blobs", "sha256", hex.EncodeToString(sum[:])), data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.blobs[digest] = data
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(data))}
+}
+
+func (l *ociTestLayout) addJSONBlob(t *testing.T, mediaType string, v any) ociDescriptor {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l.addBlob(t, mediaType, data)
+}
+
+func TestProviderOCILayout(t *testing.T) {
+	layout := newOCITestLayout(t, "application/vnd.codegame.module.binary", []byte("module binary"), "v1.1.0", "v1.2.3", "v2.0.0", "latest")
+	source, err := (&ProviderOCI{}).newSource(map[string]any{"layout": layout.dir})
+	if err != nil {
+		t.Fatalf("newSource: %s", err)
+	}
+
+	version, err := source.FindExactVersion(versions.MustParse("1"))
+	if err != nil {
+		t.Fatalf("FindExactVersion: %s", err)
+	}
+	if version.String() != "1.2.3" {
+		t.Errorf("FindExactVersion = %s, want 1.2.3", version)
+	}
+	_, err = source.FindExactVersion(versions.MustParse("3"))
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("FindExactVersion(3) error = %v, want ErrVersionNotFound", err)
+	}
+
+	var target bytes.Buffer
+	err = source.DownloadModuleBinary(&target, version)
+	if err != nil {
+		t.Fatalf("DownloadModuleBinary: %s", err)
+	}
+	if target.String() != "module binary" {
+		t.Errorf("downloaded %q, want %q", target.String(), "module binary")
+	}
+
+	// tamper with the layer
+	for digest, data := range layout.blobs {
+		if string(data) == "module binary" {
+			encoded := strings.TrimPrefix(digest, "sha256:")
+			err = os.WriteFile(filepath.Join(layout.dir, "blobs", "sha256", encoded), []byte("evil binary!!"), 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	err = source.DownloadModuleBinary(&bytes.Buffer{}, version)
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("DownloadModuleBinary with tampered layer error = %v, want ErrDigestMismatch", err)
+	}
+}
+
+// ociTestRegistry serves an OCI image layout like a registry.
+type ociTestRegistry struct {
+	url string
+	// if challenge is true, clients must request a token from the token service, else they must send token directly
+	challenge bool
+	token     string
+	// password of the token service, empty -> anonymous
+	password string
+	// the manifests of tags do not match the digests in the Docker-Content-Digest header
+	tamperManifests bool
+}
+
+func newOCITestRegistry(t *testing.T, layout *ociTestLayout, tags []string, configure func(r *ociTestRegistry)) *ociTestRegistry {
+	t.Helper()
+	var root ociIndex
+	data, err := os.ReadFile(filepath.Join(layout.dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(data, &root)
+
+	registry := &ociTestRegistry{token: "registry-token"}
+	configure(registry)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if _, password, _ := r.BasicAuth(); password != registry.password || r.URL.Query().Get("scope") != "repository:tools/module:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": registry.token})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+registry.token {
+			if registry.challenge {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test"`, registry.url))
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := strings.TrimPrefix(r.URL.Path, "/v2/tools/module/")
+		switch {
+		case path == "tags/list":
+			// one tag per page
+			last := r.URL.Query().Get("last")
+			i := 0
+			for i < len(tags) && last != "" && tags[i] != last {
+				i++
+			}
+			if last != "" {
+				i++
+			}
+			page := []string{}
+			if i < len(tags) {
+				page = append(page, tags[i])
+			}
+			if i+1 < len(tags) {
+				w.Header().Set("Link", fmt.Sprintf(`</v2/tools/module/tags/list?n=1&last=%s>; rel="next"`, tags[i]))
+			}
+			json.NewEncoder(w).Encode(map[string][]string{"tags": page})
+		case strings.HasPrefix(path, "manifests/"):
+			ref := strings.TrimPrefix(path, "manifests/")
+			tagged := !strings.Contains(ref, ":")
+			for _, m := range root.Manifests {
+				if m.Annotations[ociAnnotationRefName] == ref {
+					ref = m.Digest
+				}
+			}
+			data, ok := layout.blobs[ref]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if tagged {
+				w.Header().Set("Docker-Content-Digest", ref)
+				if registry.tamperManifests {
+					data = append(data, ' ')
+				}
+			}
+			w.Write(data)
+		case strings.HasPrefix(path, "blobs/"):
+			data, ok := layout.blobs[strings.TrimPrefix(path, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	registry.url = server.URL
+	return registry
+}
+
+func TestProviderOCIRegistry(t *testing.T) {
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	fileName := "module"
+	if runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+	content := []byte("archived module binary")
+	tarWriter.WriteHeader(&tar.Header{Name: fileName, Mode: 0o755, Size: int64(len(content))})
+	tarWriter.Write(content)
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	tags := []string{"0.9.0", "1.0.0", "latest"}
+	layout := newOCITestLayout(t, "application/vnd.oci.image.layer.v1.tar+gzip", archive.Bytes(), tags...)
+
+	tests := []struct {
+		name      string
+		configure func(r *ociTestRegistry)
+		vars      map[string]any
+		wantErr   error
+	}{
+		{"static token", func(r *ociTestRegistry) { r.token = "secret" }, map[string]any{"token": "secret"}, nil},
+		{"anonymous token", func(r *ociTestRegistry) { r.challenge = true }, nil, nil},
+		{"token exchange", func(r *ociTestRegistry) { r.challenge, r.password = true, "secret" }, map[string]any{"token": "secret"}, nil},
+		{"tampered manifest", func(r *ociTestRegistry) { r.challenge, r.tamperManifests = true, true }, nil, ErrDigestMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newOCITestRegistry(t, layout, tags, tt.configure)
+			vars := map[string]any{
+				"registry":   registry.url,
+				"repository": "tools/module",
+				"file":       "module",
+			}
+			for k, v := range tt.vars {
+				vars[k] = v
+			}
+			source, err := (&ProviderOCI{}).newSource(vars)
+			if err != nil {
+				t.Fatalf("newSource: %s", err)
+			}
+
+			version, err := source.FindExactVersion(versions.MustParse("1.0"))
+			if err != nil {
+				t.Fatalf("FindExactVersion: %s", err)
+			}
+			if version.String() != "1.0.0" {
+				t.Errorf("FindExactVersion = %s, want 1.0.0 from the second page of tags", version)
+			}
+			var target bytes.Buffer
+			err = source.DownloadModuleBinary(&target, version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("DownloadModuleBinary error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadModuleBinary: %s", err)
+			}
+			if !bytes.Equal(target.Bytes(), content) {
+				t.Errorf("downloaded %q, want %q", target.Bytes(), content)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package modules
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/request"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// maximum age of cached tag lists of release providers
+var releaseTagsCacheMaxAge = 24 * time.Hour
+
+// latestTag returns the tag with the latest version compatible with version.
+func latestTag(tags []string, version versions.Version) (string, versions.Version, error) {
+	var latestTag string
+	var latest versions.Version
+	for _, tag := range tags {
+		tagVersion, err := versions.Parse(tag)
+		if err != nil || len(tagVersion) < len(version) {
+			continue
+		}
+		if versions.Compare(tagVersion[:len(version)], version) != 0 {
+			continue
+		}
+		if latest == nil || versions.Compare(tagVersion, latest) < 0 {
+			latestTag = tag
+			latest = tagVersion
+		}
+	}
+	if latest == nil {
+		return "", nil, ErrVersionNotFound
+	}
+	return latestTag, latest, nil
+}
+
+// maximum number of pages fetched by fetchPaginated
+const maxPages = 100
+
+// fetchPaginated fetches all pages of a JSON list. Pages are requested until a page is empty, because servers may
+// return fewer entries per page than requested.
+func fetchPaginated[T any](pageURL func(page int) string, header http.Header) ([]T, error) {
+	var all []T
+	for page := 1; page <= maxPages; page++ {
+		entries, err := request.FetchJSONWithHeader[[]T](pageURL(page), header, releaseTagsCacheMaxAge)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return all, nil
+		}
+		all = append(all, entries...)
+	}
+	return nil, fmt.Errorf("more than %d pages", maxPages)
+}
+
+// releaseAssetName returns the name of the release asset containing the module binary for the current platform.
+func releaseAssetName(repository string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("%s-%s-%s.zip", repository, runtime.GOOS, runtime.GOARCH)
+	}
+	return fmt.Sprintf("%s-%s-%s.tar.gz", repository, runtime.GOOS, runtime.GOARCH)
+}
+
+// extractReleaseAsset extracts the module binary named after repository from a release asset.
+func extractReleaseAsset(asset io.Reader, repository string, target io.Writer) error {
+	if runtime.GOOS == "windows" {
+		return unzipFile(asset, repository+".exe", target)
+	}
+	return untargzFile(asset, repository, target)
+}
+
+// headerForHost returns header if target is on the same host as baseURL and nil otherwise,
+// so that credentials for the API are not sent to other hosts linked as release assets.
+func headerForHost(header http.Header, baseURL, target string) http.Header {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	t, err := url.Parse(target)
+	if err != nil || !strings.EqualFold(t.Host, base.Host) {
+		return nil
+	}
+	return header
+}
+
+// releaseAsset is a downloadable file of a release.
+type releaseAsset struct {
+	URL string
+	// 0 or -1 if unknown
+	Size int64
+	// e.g. sha256:<hex>, empty if unknown
+	Digest string
+}
+
+// downloadReleaseAsset downloads asset and extracts the module binary named after repository into target.
+// The size and digest of the asset are verified if they are known.
+func downloadReleaseAsset(target io.Writer, asset releaseAsset, header http.Header, repository string) error {
+	if asset.Size == 0 {
+		asset.Size = -1
+	}
+	// the request package reports the progress of downloads with an unknown size
+	file, status, err := request.FetchWithHeader(asset.URL, "GET", header, 0, 0, asset.Size <= 0 && !reportsProgress(target), nil)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if status >= 300 {
+		return fmt.Errorf("download %s: http status: %s", asset.URL, http.StatusText(status))
+	}
+
+	body := newDownloadProgressReader(file, target, asset.URL, repository, asset.Size)
+	verifier := &digestReader{r: body, size: asset.Size}
+	if asset.Digest == "" {
+		if asset.Size < 0 {
+			feedback.Warn(FeedbackPkg, "The release asset %s cannot be verified, because the release provides neither its checksum nor its size.", asset.URL)
+		} else {
+			feedback.Debug(FeedbackPkg, "The release provides no checksum for %s, only its size is verified.", asset.URL)
+		}
+	} else {
+		verifier, err = newDigestReader(body, ociDescriptor{Digest: asset.Digest, Size: asset.Size})
+		if err != nil {
+			return fmt.Errorf("release asset: %w", err)
+		}
+	}
+	err = extractReleaseAsset(verifier, repository, target)
+	if err != nil {
+		return err
+	}
+	return verifier.verify()
+}
@@ -0,0 +1,256 @@
+package modules
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func releaseArchive(t *testing.T, repository string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if runtime.GOOS == "windows" {
+		zipWriter := zip.NewWriter(&buf)
+		w, err := zipWriter.Create(repository + ".exe")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(content)
+		zipWriter.Close()
+		return buf.Bytes()
+	}
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	tarWriter.WriteHeader(&tar.Header{Name: repository, Mode: 0o755, Size: int64(len(content))})
+	tarWriter.Write(content)
+	tarWriter.Close()
+	gzipWriter.Close()
+	return buf.Bytes()
+}
+
+// paginate writes the page of tags requested by the page and perPageParam query parameters.
+// Like on servers with a configured maximum page size, pages contain at most maxPerPage tags if maxPerPage > 0.
+func paginate(w http.ResponseWriter, r *http.Request, tags []string, perPageParam string, maxPerPage int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get(perPageParam))
+	if maxPerPage > 0 && perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	type tag struct {
+		Name string `json:"name"`
+	}
+	result := make([]tag, 0, perPage)
+	for i := (page - 1) * perPage; i < page*perPage && i < len(tags); i++ {
+		result = append(result, tag{Name: tags[i]})
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func testTags() []string {
+	tags := make([]string, 0, 120)
+	for i := 119; i >= 0; i-- {
+		tags = append(tags, fmt.Sprintf("v0.%d.0", i))
+	}
+	// on the second page
+	tags = append(tags[:110], append([]string{"v1.2.0"}, tags[110:]...)...)
+	return tags
+}
+
+// newAssetServer serves archive on another host than the API and fails the test if the request contains authHeader.
+func newAssetServer(t *testing.T, authHeader string, archive []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(authHeader) != "" {
+			t.Errorf("%s header was sent to the asset host", authHeader)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProviderGitlab(t *testing.T) {
+	oldCacheMaxAge := releaseTagsCacheMaxAge
+	releaseTagsCacheMaxAge = 0
+	defer func() {
+		releaseTagsCacheMaxAge = oldCacheMaxAge
+	}()
+
+	content := []byte("gitlab module")
+	mux := http.NewServeMux()
+	var serverURL string
+	mux.HandleFunc("/api/v4/projects/group%2Fgo-module/repository/tags", func(w http.ResponseWriter, r *http.Request) {
+		paginate(w, r, testTags(), "per_page", 0)
+	})
+	assetServer := newAssetServer(t, "PRIVATE-TOKEN", releaseArchive(t, "go-module", content))
+	mux.HandleFunc("/api/v4/projects/group%2Fgo-module/releases/v1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"assets": {"links": [{"name": "other", "url": "%[1]s/other"}, {"name": "%[2]s", "url": "%[3]s/asset"}]}}`, serverURL, releaseAssetName("go-module"), assetServer.URL)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.URL.Path = r.URL.EscapedPath()
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	vars := map[string]any{
+		"base_url": server.URL,
+		"project":  "group/go-module",
+		"token":    "secret",
+	}
+	testReleaseProvider(t, &ProviderGitlab{}, vars, content)
+}
+
+func TestProviderGitea(t *testing.T) {
+	oldCacheMaxAge := releaseTagsCacheMaxAge
+	releaseTagsCacheMaxAge = 0
+	defer func() {
+		releaseTagsCacheMaxAge = oldCacheMaxAge
+	}()
+
+	content := []byte("gitea module")
+	mux := http.NewServeMux()
+	var serverURL string
+	mux.HandleFunc("/api/v1/repos/owner/go-module/tags", func(w http.ResponseWriter, r *http.Request) {
+		paginate(w, r, testTags(), "limit", 30)
+	})
+	archive := releaseArchive(t, "go-module", content)
+	assetServer := newAssetServer(t, "Authorization", archive)
+	mux.HandleFunc("/api/v1/repos/owner/go-module/releases/tags/v1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"assets": [{"name": "%[2]s", "size": %[3]d, "browser_download_url": "%[1]s/asset"}]}`, serverURL, releaseAssetName("go-module"), len(archive))
+	})
+	// the download is redirected to another host
+	mux.HandleFunc("/asset", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, assetServer.URL+"/asset", http.StatusFound)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	vars := map[string]any{
+		"base_url":   server.URL,
+		"owner":      "owner",
+		"repository": "go-module",
+		"token":      "secret",
+	}
+	testReleaseProvider(t, &ProviderGitea{}, vars, content)
+}
+
+func TestProviderGithub(t *testing.T) {
+	oldCacheMaxAge := releaseTagsCacheMaxAge
+	releaseTagsCacheMaxAge = 0
+	defer func() {
+		releaseTagsCacheMaxAge = oldCacheMaxAge
+	}()
+
+	content := []byte("github module")
+	archive := releaseArchive(t, "go-module", content)
+	sum := sha256.Sum256(archive)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	mux := http.NewServeMux()
+	var serverURL string
+	mux.HandleFunc("/repos/owner/go-module/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name": "v0.9.0"}, {"name": "v1.2.0"}]`)
+	})
+	mux.HandleFunc("/repos/owner/go-module/releases/tags/v1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"assets": [{"name": "%[2]s", "size": %[3]d, "digest": "%[4]s", "browser_download_url": "%[1]s/asset"}]}`, serverURL, releaseAssetName("go-module"), len(archive), digest)
+	})
+	mux.HandleFunc("/asset", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	serverURL = server.URL
+
+	vars := map[string]any{
+		"base_url":   server.URL + "/",
+		"owner":      "owner",
+		"repository": "go-module",
+	}
+	source, err := (&ProviderGithub{}).newSource(vars)
+	if err != nil {
+		t.Fatalf("newSource: %s", err)
+	}
+	version, err := source.FindExactVersion(versions.MustParse("1"))
+	if err != nil {
+		t.Fatalf("FindExactVersion: %s", err)
+	}
+	if version.String() != "1.2.0" {
+		t.Errorf("FindExactVersion = %s, want 1.2.0", version)
+	}
+	var target bytes.Buffer
+	err = source.DownloadModuleBinary(&target, version)
+	if err != nil {
+		t.Fatalf("DownloadModuleBinary: %s", err)
+	}
+	if !bytes.Equal(target.Bytes(), content) {
+		t.Errorf("downloaded %q, want %q", target.Bytes(), content)
+	}
+
+	// tampered asset
+	archive = releaseArchive(t, "go-module", []byte("evil module!!"))
+	err = source.DownloadModuleBinary(&bytes.Buffer{}, version)
+	if !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("DownloadModuleBinary with tampered asset error = %v, want ErrDigestMismatch", err)
+	}
+}
+
+func testReleaseProvider(t *testing.T, provider provider, vars map[string]any, content []byte) {
+	t.Helper()
+	source, err := provider.newSource(vars)
+	if err != nil {
+		t.Fatalf("newSource: %s", err)
+	}
+
+	version, err := source.FindExactVersion(versions.MustParse("1"))
+	if err != nil {
+		t.Fatalf("FindExactVersion: %s", err)
+	}
+	if version.String() != "1.2.0" {
+		t.Errorf("FindExactVersion = %s, want 1.2.0", version)
+	}
+
+	var target bytes.Buffer
+	err = source.DownloadModuleBinary(&target, version)
+	if err != nil {
+		t.Fatalf("DownloadModuleBinary: %s", err)
+	}
+	if !bytes.Equal(target.Bytes(), content) {
+		t.Errorf("downloaded %q, want %q", target.Bytes(), content)
+	}
+
+	delete(vars, "token")
+	source, err = provider.newSource(vars)
+	if err != nil {
+		t.Fatalf("newSource: %s", err)
+	}
+	_, err = source.FindExactVersion(versions.MustParse("1"))
+	if err == nil {
+		t.Error("FindExactVersion without token succeeded")
+	}
+}
@@ -0,0 +1,47 @@
+package modules
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSourceConfigErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  provider
+		vars      map[string]any
+		wantField string
+		wantErr   string
+	}{
+		{"missing field", &ProviderGithub{}, map[string]any{"owner": "code-game-project"}, "repository", "field 'source.repository': required field is missing"},
+		{"wrong type", &ProviderGithub{}, map[string]any{"owner": 42, "repository": "go-module"}, "owner", "field 'source.owner': value must be a string"},
+		{"unknown field", &ProviderGithub{}, map[string]any{"owner": "code-game-project", "repository": "go-module", "repo": "go-module"}, "repo", "field 'source.repo': unknown field for provider 'github'"},
+		{"wrong list type", &ProviderLocal{}, map[string]any{"paths": []any{"a", 1}}, "paths.1", "field 'source.paths.1': value must be a string"},
+		{"exclusive fields", &ProviderOCI{}, map[string]any{"layout": "dir", "registry": "ghcr.io", "repository": "module"}, "layout", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.provider.newSource(test.vars)
+			var configErr *SourceConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("newSource error = %v, want *SourceConfigError", err)
+			}
+			if configErr.Field != test.wantField {
+				t.Errorf("field = %s, want %s", configErr.Field, test.wantField)
+			}
+			if test.wantErr != "" && err.Error() != test.wantErr {
+				t.Errorf("error = %q, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSourceConfigDefaults(t *testing.T) {
+	source, err := (&ProviderGitlab{}).newSource(map[string]any{"project": "group/module"})
+	if err != nil {
+		t.Fatalf("newSource: %s", err)
+	}
+	if gitlab := source.(*GitlabSource); gitlab.BaseURL != "https://gitlab.com" {
+		t.Errorf("base_url = %s, want https://gitlab.com", gitlab.BaseURL)
+	}
+}
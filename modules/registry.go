@@ -0,0 +1,176 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/code-game-project/cli-utils/config"
+	"github.com/code-game-project/cli-utils/feedback"
+)
+
+// DefaultRegistry is the registry used by the package-level functions.
+// It loads the lang_modules.json file in the config directory.
+var DefaultRegistry = NewRegistry(filepath.Join(config.ConfigDir(), "lang_modules.json"))
+
+// Registry lazily loads the modules of a language modules config file. It is safe for concurrent use.
+type Registry struct {
+	configPath string
+
+	mu              sync.Mutex
+	rawModules      map[string]rawModule
+	effectiveConfig map[string]any
+	entries         map[string]*registryEntry
+
+	availableMu        sync.Mutex
+	availableLanguages map[string]AvailableLanguage
+}
+
+type registryEntry struct {
+	mu     sync.Mutex
+	module *Module
+}
+
+// NewRegistry returns a registry for the language modules config file at configPath.
+// The file is read when a module is first requested.
+func NewRegistry(configPath string) *Registry {
+	return &Registry{
+		configPath: configPath,
+		entries:    make(map[string]*registryEntry),
+	}
+}
+
+// Reload discards all loaded modules and reads the config file again.
+func (r *Registry) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rawModules = nil
+	r.effectiveConfig = nil
+	r.entries = make(map[string]*registryEntry)
+
+	r.availableMu.Lock()
+	r.availableLanguages = nil
+	r.availableMu.Unlock()
+
+	return r.loadRawModules()
+}
+
+// LoadModule returns the module for lang. Successfully loaded modules are cached until the next call to Reload.
+func (r *Registry) LoadModule(lang string) (*Module, error) {
+	r.mu.Lock()
+	if r.rawModules == nil {
+		err := r.loadRawModules()
+		if err != nil {
+			r.mu.Unlock()
+			return nil, err
+		}
+	}
+	raw, ok := r.rawModules[lang]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no module available for lang '%s'", lang)
+	}
+	entry, ok := r.entries[lang]
+	if !ok {
+		entry = &registryEntry{}
+		r.entries[lang] = entry
+	}
+	r.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.module != nil {
+		return entry.module, nil
+	}
+	module, err := newModule(lang, raw)
+	if err != nil {
+		return nil, fmt.Errorf("lang '%s': %w", lang, err)
+	}
+	entry.module = module
+	return module, nil
+}
+
+// AvailableLanguages returns all languages in the config file and the project types they support.
+func (r *Registry) AvailableLanguages() map[string]AvailableLanguage { // name -> display name
+	r.mu.Lock()
+	if r.rawModules == nil {
+		err := r.loadRawModules()
+		if err != nil {
+			feedback.Error(FeedbackPkg, "Failed to load available languages: %s", err)
+		}
+	}
+	rawModules := r.rawModules
+	r.mu.Unlock()
+
+	r.availableMu.Lock()
+	defer r.availableMu.Unlock()
+	if r.availableLanguages == nil {
+		r.availableLanguages = make(map[string]AvailableLanguage, len(rawModules))
+		for n, m := range rawModules {
+			versions, _, err := loadJSONObjectInlineOrLocalOrRemote[map[string]json.RawMessage](m.CodeGameToLibraryVersions, m.base)
+			if err != nil {
+				feedback.Error(FeedbackPkg, "Failed to load supported project types of %s module: %s", n, err)
+				continue
+			}
+			for projectType, versionMap := range versions {
+				if string(versionMap) == "null" {
+					delete(versions, projectType)
+				}
+			}
+			_, supportsClient := versions[ProjectTypeClient]
+			_, supportsServer := versions[ProjectTypeServer]
+			r.availableLanguages[n] = AvailableLanguage{
+				DisplayName:    m.DisplayName,
+				SupportsClient: supportsClient,
+				SupportsServer: supportsServer,
+				ProjectTypes:   sortedProjectTypes(versions),
+			}
+		}
+	}
+
+	languages := make(map[string]AvailableLanguage, len(r.availableLanguages))
+	for n, l := range r.availableLanguages {
+		languages[n] = l
+	}
+	return languages
+}
+
+// EffectiveConfig returns the language modules config with all layers merged and relative paths resolved.
+func (r *Registry) EffectiveConfig() (json.RawMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rawModules == nil {
+		err := r.loadRawModules()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.MarshalIndent(r.effectiveConfig, "", "  ")
+}
+
+// loadRawModules must be called with r.mu locked.
+func (r *Registry) loadRawModules() error {
+	config, err := loadLayeredConfig(r.configPath)
+	if err != nil {
+		return fmt.Errorf("load language modules config file: %w", err)
+	}
+
+	rawModules := make(map[string]rawModule, len(config))
+	for lang, entry := range config {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("lang '%s': %w", lang, err)
+		}
+		var m rawModule
+		err = json.Unmarshal(data, &m)
+		if err != nil {
+			return fmt.Errorf("decode language modules config file: lang '%s': %w", lang, err)
+		}
+		m.base = r.configPath
+		rawModules[lang] = m
+	}
+	r.rawModules = rawModules
+	r.effectiveConfig = config
+	return nil
+}
@@ -0,0 +1,84 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const testLangModules = `{
+	"go": {
+		"display_name": "Go",
+		"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+		"library_to_module_versions": {"client": {"0.9": "0.4"}},
+		"codegame_to_library_versions": {"client": {"0.9": "0.9"}, "server": {"0.9": "0.3"}}
+	},
+	"js": {
+		"display_name": "JavaScript",
+		"source": {"provider": "github", "owner": "code-game-project", "repository": "js-module"},
+		"library_to_module_versions": {"client": {"0.9": "0.2"}},
+		"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+	}
+}`
+
+func newTestRegistry(t *testing.T, langModules string) *Registry {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lang_modules.json")
+	err := os.WriteFile(path, []byte(langModules), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRegistry(path)
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	registry := newTestRegistry(t, testLangModules)
+
+	var wg sync.WaitGroup
+	loaded := make([]*Module, 16)
+	for i := range loaded {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m, err := registry.LoadModule("go")
+			if err != nil {
+				t.Errorf("LoadModule: %s", err)
+				return
+			}
+			loaded[i] = m
+			if langs := registry.AvailableLanguages(); len(langs) != 2 {
+				t.Errorf("AvailableLanguages = %d languages, want 2", len(langs))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, m := range loaded {
+		if m != loaded[0] {
+			t.Fatalf("LoadModule returned different modules for the same language")
+		}
+	}
+
+	err := registry.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %s", err)
+	}
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	if m == loaded[0] {
+		t.Errorf("LoadModule returned cached module after Reload")
+	}
+	if langs := registry.AvailableLanguages(); !langs["go"].SupportsServer || langs["js"].SupportsServer {
+		t.Errorf("AvailableLanguages = %v", langs)
+	}
+}
+
+func TestRegistry_UnknownLanguage(t *testing.T) {
+	registry := newTestRegistry(t, testLangModules)
+	if _, err := registry.LoadModule("cobol"); err == nil {
+		t.Errorf("LoadModule returned no error for unknown language")
+	}
+}
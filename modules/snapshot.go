@@ -0,0 +1,361 @@
+package modules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+var snapshotPath = filepath.Join(xdg.DataHome, "codegame", "snapshots")
+
+// SnapshotsToKeep is the number of snapshots kept per project for manual rollback.
+var SnapshotsToKeep = 5
+
+// Snapshot is a copy of the files of a project which are not ignored by .gitignore.
+type Snapshot struct {
+	ID          string    `json:"id"`
+	ProjectRoot string    `json:"project_root"`
+	CreatedAt   time.Time `json:"created_at"`
+	Reason      string    `json:"reason"`
+	Files       []string  `json:"files"`
+	Dirs        []string  `json:"dirs"`
+	// path -> target
+	Symlinks map[string]string `json:"symlinks,omitempty"`
+
+	dir string
+}
+
+func projectSnapshotsDir(projectRoot string) string {
+	hash := sha256.Sum256([]byte(projectRoot))
+	return filepath.Join(snapshotPath, hex.EncodeToString(hash[:8]))
+}
+
+// CreateSnapshot copies the files and symbolic links of the project at projectRoot which are not ignored by .gitignore
+// into a new snapshot. The .gitignore files are stored as well, so that Restore uses the same rules.
+// Only the newest SnapshotsToKeep snapshots of the project are kept.
+func CreateSnapshot(projectRoot, reason string) (*Snapshot, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	files, dirs, symlinks, err := trackedFiles(projectRoot, projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("list project files: %w", err)
+	}
+	links := make(map[string]string, len(symlinks))
+	for _, l := range symlinks {
+		links[l], err = os.Readlink(filepath.Join(projectRoot, l))
+		if err != nil {
+			return nil, fmt.Errorf("read symbolic link %s: %w", l, err)
+		}
+	}
+
+	now := time.Now()
+	snapshot := &Snapshot{
+		ID:          now.UTC().Format("20060102T150405.000000000"),
+		ProjectRoot: projectRoot,
+		CreatedAt:   now,
+		Reason:      reason,
+		Files:       files,
+		Dirs:        dirs,
+		Symlinks:    links,
+	}
+	snapshot.dir = filepath.Join(projectSnapshotsDir(projectRoot), snapshot.ID)
+	err = os.MkdirAll(snapshot.dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create snapshot directory: %w", err)
+	}
+
+	for _, f := range files {
+		err = copyFile(filepath.Join(projectRoot, f), filepath.Join(snapshot.dir, "files", f))
+		if err != nil {
+			os.RemoveAll(snapshot.dir)
+			return nil, fmt.Errorf("copy %s: %w", f, err)
+		}
+	}
+	err = snapshot.saveIgnoreFiles()
+	if err != nil {
+		os.RemoveAll(snapshot.dir)
+		return nil, fmt.Errorf("copy .gitignore files: %w", err)
+	}
+
+	manifest, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		os.RemoveAll(snapshot.dir)
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(snapshot.dir, "snapshot.json"), manifest, 0o644)
+	if err != nil {
+		os.RemoveAll(snapshot.dir)
+		return nil, fmt.Errorf("write snapshot manifest: %w", err)
+	}
+
+	err = pruneSnapshots(projectRoot, SnapshotsToKeep)
+	if err != nil {
+		feedback.Warn(FeedbackPkg, "Failed to remove old snapshots: %s", err)
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots returns all snapshots of the project at projectRoot (newest first).
+func ListSnapshots(projectRoot string) ([]*Snapshot, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	dir := projectSnapshotsDir(projectRoot)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	snapshots := make([]*Snapshot, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name(), "snapshot.json"))
+		if err != nil {
+			// incomplete snapshot
+			continue
+		}
+		snapshot := &Snapshot{}
+		err = json.Unmarshal(data, snapshot)
+		if err != nil {
+			feedback.Warn(FeedbackPkg, "Invalid snapshot manifest in %s: %s", e.Name(), err)
+			continue
+		}
+		snapshot.dir = filepath.Join(dir, e.Name())
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ID > snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// saveIgnoreFiles copies the .gitignore files of all directories in the snapshot (even if they ignore themselves)
+// to the ignore directory of the snapshot.
+func (s *Snapshot) saveIgnoreFiles() error {
+	err := os.MkdirAll(filepath.Join(s.dir, "ignore"), 0o755)
+	if err != nil {
+		return err
+	}
+	for _, d := range append([]string{"."}, s.Dirs...) {
+		name := filepath.Join(filepath.FromSlash(d), ".gitignore")
+		stat, err := os.Lstat(filepath.Join(s.ProjectRoot, name))
+		if err != nil || !stat.Mode().IsRegular() {
+			continue
+		}
+		err = copyFile(filepath.Join(s.ProjectRoot, name), filepath.Join(s.dir, "ignore", name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore resets the project to the state of the snapshot.
+// Files and symbolic links which were created after the snapshot and are not ignored by the .gitignore files
+// at the time of the snapshot are deleted.
+func (s *Snapshot) Restore() error {
+	ignoreRoot := filepath.Join(s.dir, "ignore")
+	if _, err := os.Stat(ignoreRoot); err != nil {
+		// snapshots created by older versions don't contain the .gitignore files
+		ignoreRoot = s.ProjectRoot
+	}
+	files, dirs, symlinks, err := trackedFiles(s.ProjectRoot, ignoreRoot)
+	if err != nil {
+		return fmt.Errorf("list project files: %w", err)
+	}
+
+	snapshotFiles := make(map[string]bool, len(s.Files))
+	for _, f := range s.Files {
+		snapshotFiles[f] = true
+	}
+	snapshotDirs := make(map[string]bool, len(s.Dirs))
+	for _, d := range s.Dirs {
+		snapshotDirs[d] = true
+	}
+
+	var errs []error
+	for _, f := range append(files, symlinks...) {
+		if _, ok := s.Symlinks[f]; !snapshotFiles[f] && !ok {
+			err = os.Remove(filepath.Join(s.ProjectRoot, f))
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	// deepest directories first
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/")
+	})
+	for _, d := range dirs {
+		if !snapshotDirs[d] {
+			// fails if the directory still contains ignored files
+			os.Remove(filepath.Join(s.ProjectRoot, d))
+		}
+	}
+
+	for _, d := range s.Dirs {
+		err = os.MkdirAll(filepath.Join(s.ProjectRoot, d), 0o755)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", d, err))
+		}
+	}
+	for _, f := range s.Files {
+		dst := filepath.Join(s.ProjectRoot, f)
+		// don't write to the target of a symbolic link created after the snapshot
+		if stat, err := os.Lstat(dst); err == nil && stat.Mode()&os.ModeSymlink != 0 {
+			os.Remove(dst)
+		}
+		err = copyFile(filepath.Join(s.dir, "files", f), dst)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", f, err))
+		}
+	}
+	for l, target := range s.Symlinks {
+		err = restoreSymlink(filepath.Join(s.ProjectRoot, l), target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", l, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreSymlink replaces the file at path with a symbolic link to target unless it already is one.
+func restoreSymlink(path, target string) error {
+	if current, err := os.Readlink(path); err == nil && current == target {
+		return nil
+	}
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(target, path)
+}
+
+// Remove deletes the snapshot.
+func (s *Snapshot) Remove() error {
+	return os.RemoveAll(s.dir)
+}
+
+func pruneSnapshots(projectRoot string, keep int) error {
+	snapshots, err := ListSnapshots(projectRoot)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= keep {
+		return nil
+	}
+	var errs []error
+	for _, s := range snapshots[keep:] {
+		errs = append(errs, s.Remove())
+	}
+	return errors.Join(errs...)
+}
+
+func copyFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+	stat, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, stat.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dstFile, srcFile)
+	if err != nil {
+		dstFile.Close()
+		return err
+	}
+	err = dstFile.Close()
+	if err != nil {
+		return err
+	}
+	return os.Chmod(dst, stat.Mode().Perm())
+}
+
+// WithSnapshot snapshots the current project before calling fn and restores the snapshot if fn returns an error.
+func WithSnapshot(reason string, fn func() error) error {
+	projectRoot, err := cgfile.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+	snapshot, err := CreateSnapshot(projectRoot, reason)
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+
+	err = fn()
+	if err != nil {
+		feedback.Warn(FeedbackPkg, "Restoring project files from snapshot %s...", snapshot.ID)
+		restoreErr := snapshot.Restore()
+		if restoreErr != nil {
+			return errors.Join(err, fmt.Errorf("restore snapshot %s: %w", snapshot.ID, restoreErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// ExecUpdateClientTransactional is like ExecUpdateClient but restores the project files if the update fails.
+func (m *Module) ExecUpdateClientTransactional(language, gameURL string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	err = WithSnapshot("update client", func() error {
+		modVersion, err = m.ExecUpdateClient(language, gameURL, cgVersion)
+		return err
+	})
+	return modVersion, err
+}
+
+// ExecUpdateServerTransactional is like ExecUpdateServer but restores the project files if the update fails.
+func (m *Module) ExecUpdateServerTransactional(language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	err = WithSnapshot("update server", func() error {
+		modVersion, err = m.ExecUpdateServer(language, cgVersion)
+		return err
+	})
+	return modVersion, err
+}
+
+// ExecUpdateProjectTransactional is like ExecUpdateProject but restores the project files if the update fails.
+func (m *Module) ExecUpdateProjectTransactional(projectType, language string, cgVersion versions.Version) (modVersion versions.Version, err error) {
+	err = WithSnapshot("update "+projectType, func() error {
+		modVersion, err = m.ExecUpdateProject(projectType, language, cgVersion)
+		return err
+	})
+	return modVersion, err
+}
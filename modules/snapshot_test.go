@@ -0,0 +1,240 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	oldSnapshotPath := snapshotPath
+	snapshotPath = t.TempDir()
+	defer func() {
+		snapshotPath = oldSnapshotPath
+	}()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":         "/build/\n*.log\n!keep.log\n",
+		".codegame.json":     `{"mod_version": "1.0"}`,
+		"src/main.go":        "package main",
+		"src/debug.log":      "old log",
+		"src/keep.log":       "keep",
+		"build/binary":       "old binary",
+		"src/sub/.gitignore": "generated.go\n",
+	})
+
+	snapshot, err := CreateSnapshot(root, "test")
+	if err != nil {
+		t.Fatalf("CreateSnapshot: %s", err)
+	}
+
+	writeFiles(t, root, map[string]string{
+		".codegame.json":       `{"mod_version": "2.0"}`,
+		"src/new/file.go":      "package new",
+		"src/debug.log":        "new log",
+		"build/binary":         "new binary",
+		"src/sub/generated.go": "generated",
+	})
+	err = os.Remove(filepath.Join(root, "src", "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = snapshot.Restore()
+	if err != nil {
+		t.Fatalf("Restore: %s", err)
+	}
+
+	want := map[string]string{
+		".codegame.json":       `{"mod_version": "1.0"}`,
+		"src/main.go":          "package main",
+		"src/keep.log":         "keep",
+		"src/debug.log":        "new log",
+		"build/binary":         "new binary",
+		"src/sub/generated.go": "generated",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Errorf("read %s: %s", name, err)
+		} else if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "new")); !os.IsNotExist(err) {
+		t.Errorf("directory created after the snapshot was not removed")
+	}
+}
+
+func TestSnapshotEmptyProject(t *testing.T) {
+	oldSnapshotPath := snapshotPath
+	snapshotPath = t.TempDir()
+	defer func() {
+		snapshotPath = oldSnapshotPath
+	}()
+
+	root := t.TempDir()
+	snapshot, err := CreateSnapshot(root, "test")
+	if err != nil {
+		t.Fatalf("CreateSnapshot: %s", err)
+	}
+	if len(snapshot.Files) != 0 {
+		t.Errorf("snapshot files = %v, want none", snapshot.Files)
+	}
+
+	writeFiles(t, root, map[string]string{"new.go": "package main"})
+	err = snapshot.Restore()
+	if err != nil {
+		t.Fatalf("Restore: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "new.go")); !os.IsNotExist(err) {
+		t.Errorf("new.go still exists after restoring the empty snapshot")
+	}
+}
+
+func TestSnapshotRestore_ignoreRulesOfSnapshot(t *testing.T) {
+	oldSnapshotPath := snapshotPath
+	snapshotPath = t.TempDir()
+	defer func() {
+		snapshotPath = oldSnapshotPath
+	}()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore": "*.log\n",
+		"main.go":    "package main",
+		"debug.log":  "log",
+	})
+	snapshot, err := CreateSnapshot(root, "test")
+	if err != nil {
+		t.Fatalf("CreateSnapshot: %s", err)
+	}
+
+	// the update stops ignoring log files and creates a new one
+	writeFiles(t, root, map[string]string{
+		".gitignore": "",
+		"new.log":    "new",
+	})
+	err = snapshot.Restore()
+	if err != nil {
+		t.Fatalf("Restore: %s", err)
+	}
+
+	for _, name := range []string{"debug.log", "new.log"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			t.Errorf("%s ignored at the time of the snapshot was removed: %s", name, err)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, ".gitignore")); err != nil || string(data) != "*.log\n" {
+		t.Errorf(".gitignore = %q, %v, want the content of the snapshot", data, err)
+	}
+}
+
+func TestSnapshotRestore_symlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links requires special privileges on Windows")
+	}
+	oldSnapshotPath := snapshotPath
+	snapshotPath = t.TempDir()
+	defer func() {
+		snapshotPath = oldSnapshotPath
+	}()
+
+	mustDo := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"target.txt": "target",
+		"other.txt":  "other",
+		"file":       "file",
+	})
+	mustDo(os.Symlink("target.txt", filepath.Join(root, "link")))
+	mustDo(os.Symlink("target.txt", filepath.Join(root, "changed")))
+
+	snapshot, err := CreateSnapshot(root, "test")
+	if err != nil {
+		t.Fatalf("CreateSnapshot: %s", err)
+	}
+	if len(snapshot.Symlinks) != 2 || snapshot.Symlinks["link"] != "target.txt" {
+		t.Fatalf("snapshot symlinks = %v, want link and changed", snapshot.Symlinks)
+	}
+
+	mustDo(os.Remove(filepath.Join(root, "link")))
+	mustDo(os.Remove(filepath.Join(root, "changed")))
+	mustDo(os.Symlink("other.txt", filepath.Join(root, "changed")))
+	mustDo(os.Remove(filepath.Join(root, "file")))
+	// restoring the file must not modify the target of the link
+	mustDo(os.Symlink("other.txt", filepath.Join(root, "file")))
+	mustDo(os.Symlink("target.txt", filepath.Join(root, "new_link")))
+
+	err = snapshot.Restore()
+	if err != nil {
+		t.Fatalf("Restore: %s", err)
+	}
+
+	for link, want := range map[string]string{"link": "target.txt", "changed": "target.txt"} {
+		if target, err := os.Readlink(filepath.Join(root, link)); err != nil || target != want {
+			t.Errorf("link %s = %q, %v, want %q", link, target, err, want)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(root, "new_link")); !os.IsNotExist(err) {
+		t.Errorf("link created after the snapshot was not removed")
+	}
+	if stat, err := os.Lstat(filepath.Join(root, "file")); err != nil || !stat.Mode().IsRegular() {
+		t.Errorf("file was not restored as a regular file")
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "other.txt")); string(data) != "other" {
+		t.Errorf("other.txt = %q, want other", data)
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	oldSnapshotPath := snapshotPath
+	snapshotPath = t.TempDir()
+	oldSnapshotsToKeep := SnapshotsToKeep
+	SnapshotsToKeep = 2
+	defer func() {
+		snapshotPath = oldSnapshotPath
+		SnapshotsToKeep = oldSnapshotsToKeep
+	}()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"file": "content"})
+	var ids []string
+	for i := 0; i < 3; i++ {
+		snapshot, err := CreateSnapshot(root, "test")
+		if err != nil {
+			t.Fatalf("CreateSnapshot: %s", err)
+		}
+		ids = append(ids, snapshot.ID)
+	}
+
+	snapshots, err := ListSnapshots(root)
+	if err != nil {
+		t.Fatalf("ListSnapshots: %s", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != ids[2] || snapshots[1].ID != ids[1] {
+		t.Errorf("ListSnapshots = %v, want the 2 newest snapshots", snapshots)
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
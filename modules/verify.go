@@ -0,0 +1,384 @@
+package modules
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+var knownActions = []Action{ActionInfo, ActionCreate, ActionUpdate, ActionRunClient, ActionRunServer, ActionBuild, ActionTest}
+
+type VerifyOptions struct {
+	// Lang is passed to the create and update actions.
+	// The library versions of the module are compared with the lang_modules.json entry of Lang (if available).
+	Lang string
+	// GameURL is passed to the create and update actions of clients.
+	GameURL string
+	// Registry contains the lang_modules.json entry of Lang. Default: DefaultRegistry
+	Registry *Registry
+}
+
+type VerifyCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	ModulePath string        `json:"module_path"`
+	Info       *ModuleInfo   `json:"info,omitempty"`
+	Checks     []VerifyCheck `json:"checks"`
+}
+
+// Passed returns true if all checks passed.
+func (r *VerifyReport) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *VerifyReport) check(name string, err error) bool {
+	check := VerifyCheck{
+		Name:   name,
+		Passed: err == nil,
+	}
+	if err != nil {
+		check.Message = err.Error()
+	}
+	r.Checks = append(r.Checks, check)
+	return err == nil
+}
+
+// Verify validates the info response of the module executable at modulePath and runs the create and update actions
+// for each supported project type in temporary directories.
+func Verify(modulePath string, options VerifyOptions) *VerifyReport {
+	report := &VerifyReport{
+		ModulePath: modulePath,
+		Checks:     make([]VerifyCheck, 0),
+	}
+
+	info, err := execInfo(modulePath, nil)
+	if !report.check("info", err) {
+		return report
+	}
+	report.Info = &info
+
+	report.check("version", verifyVersion(info))
+	report.check("actions", verifyActions(info))
+	report.check("project_types", verifyProjectTypes(info))
+	report.check("library_versions", verifyLibraryVersions(info))
+	if options.Lang != "" {
+		registry := options.Registry
+		if registry == nil {
+			registry = DefaultRegistry
+		}
+		if _, ok := registry.AvailableLanguages()[options.Lang]; ok {
+			report.check("registry", verifyRegistry(info, registry, options.Lang))
+		}
+	}
+
+	if !containsAction(info.Actions, ActionCreate) {
+		return report
+	}
+	for _, projectType := range info.ProjectTypes {
+		if !ValidProjectTypeName(projectType) {
+			continue
+		}
+		verifyCreateAndUpdate(report, modulePath, info, projectType, options)
+	}
+	return report
+}
+
+func verifyVersion(info ModuleInfo) error {
+	if info.Version == nil {
+		return errors.New("missing 'version' field")
+	}
+	return nil
+}
+
+func verifyActions(info ModuleInfo) error {
+	var unknown []string
+	for _, a := range info.Actions {
+		if !containsAction(knownActions, a) {
+			unknown = append(unknown, string(a))
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown actions: %s", strings.Join(unknown, ", "))
+	}
+	if !containsAction(info.Actions, ActionInfo) {
+		return fmt.Errorf("missing '%s' action", ActionInfo)
+	}
+	return nil
+}
+
+func verifyProjectTypes(info ModuleInfo) error {
+	var invalid []string
+	for _, p := range info.ProjectTypes {
+		if !ValidProjectTypeName(p) {
+			invalid = append(invalid, p)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid project type names: %s", strings.Join(invalid, ", "))
+	}
+	for _, p := range info.ProjectTypes {
+		if err := checkProjectType(info, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyLibraryVersions(info ModuleInfo) error {
+	var errs []string
+	for projectType := range info.LibraryVersions {
+		if !contains(info.ProjectTypes, projectType) {
+			errs = append(errs, fmt.Sprintf("library versions for undeclared project type '%s'", projectType))
+		}
+	}
+	for _, projectType := range info.ProjectTypes {
+		if len(info.LibraryVersions[projectType]) == 0 {
+			errs = append(errs, fmt.Sprintf("no library versions for project type '%s'", projectType))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// verifyRegistry checks that the module supports all library versions, which are mapped to its version in lang_modules.json.
+func verifyRegistry(info ModuleInfo, registry *Registry, lang string) error {
+	module, err := registry.LoadModule(lang)
+	if err != nil {
+		return fmt.Errorf("load '%s' module: %w", lang, err)
+	}
+	if module.provider.Name() == "local" {
+		return nil
+	}
+	var errs []string
+	for projectType, versionMap := range module.libToModVersions {
+		for lib, mod := range versionMap {
+			modVersion, err := versions.Parse(mod)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("invalid module version '%s' in %s library version map", mod, projectType))
+				continue
+			}
+			if !sameVersion(modVersion, info.Version) {
+				continue
+			}
+			libVersion, err := versions.Parse(lib)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("invalid library version '%s' in %s library version map", lib, projectType))
+				continue
+			}
+			if !containsVersion(info.LibraryVersions[projectType], libVersion) {
+				errs = append(errs, fmt.Sprintf("%s library version %s is mapped to module version %s but not supported by the module", projectType, lib, mod))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func verifyCreateAndUpdate(report *VerifyReport, modulePath string, info ModuleInfo, projectType string, options VerifyOptions) {
+	dir, err := os.MkdirTemp("", "codegame-module-verify-*")
+	if err != nil {
+		report.check(projectType+"/create", fmt.Errorf("create scratch directory: %w", err))
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	var libraryVersion *string
+	if libVersions := info.LibraryVersions[projectType]; len(libVersions) > 0 {
+		latest := libVersions[0]
+		for _, v := range libVersions {
+			if versions.Compare(latest, v) == 1 {
+				latest = v
+			}
+		}
+		v := latest.String()
+		libraryVersion = &v
+	}
+	var gameURL *string
+	if projectType == ProjectTypeClient {
+		gameURL = &options.GameURL
+	}
+	protoType, customName := protoProjectType(projectType)
+
+	err = verifyExec(modulePath, info, dir, ActionCreate, &ActionCreateData{
+		ProjectType:     protoType,
+		ProjectTypeName: customName,
+		Language:        options.Lang,
+		GameName:        "verify",
+		GameURL:         gameURL,
+		LibraryVersion:  libraryVersion,
+	})
+	if !report.check(projectType+"/create", err) {
+		return
+	}
+
+	report.check(projectType+"/codegame_file", verifyCodeGameFile(dir, projectType, options.Lang))
+
+	if !containsAction(info.Actions, ActionUpdate) {
+		return
+	}
+
+	updateData := &ActionUpdateData{
+		ProjectType:     protoType,
+		ProjectTypeName: customName,
+		Language:        options.Lang,
+		GameURL:         gameURL,
+		LibraryVersion:  libraryVersion,
+	}
+	err = verifyExec(modulePath, info, dir, ActionUpdate, updateData)
+	if !report.check(projectType+"/update", err) {
+		return
+	}
+	report.check(projectType+"/update_idempotent", verifyIdempotentUpdate(modulePath, info, dir, updateData))
+}
+
+func verifyIdempotentUpdate(modulePath string, info ModuleInfo, dir string, updateData *ActionUpdateData) error {
+	before, err := hashDir(dir)
+	if err != nil {
+		return err
+	}
+	err = verifyExec(modulePath, info, dir, ActionUpdate, updateData)
+	if err != nil {
+		return err
+	}
+	after, err := hashDir(dir)
+	if err != nil {
+		return err
+	}
+	if before != after {
+		return errors.New("the second update modified the project")
+	}
+	return nil
+}
+
+func verifyExec(modulePath string, info ModuleInfo, dir string, action Action, actionData proto.Message) error {
+	var output bytes.Buffer
+	_, err := ExecAction(modulePath, info, action, actionData, ExecOptions{
+		Dir:    dir,
+		Stdin:  bytes.NewReader(nil),
+		Stdout: &output,
+		Stderr: &output,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+func verifyCodeGameFile(dir, projectType, lang string) error {
+	data, err := cgfile.Load(dir)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	if data.GameName != "verify" {
+		errs = append(errs, fmt.Sprintf("game_name is '%s', expected 'verify'", data.GameName))
+	}
+	if data.ProjectType != projectType {
+		errs = append(errs, fmt.Sprintf("project_type is '%s', expected '%s'", data.ProjectType, projectType))
+	}
+	if data.Language != lang {
+		errs = append(errs, fmt.Sprintf("language is '%s', expected '%s'", data.Language, lang))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// hashDir returns a hash of the paths and contents of all files in dir.
+func hashDir(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(rel))
+		_, err = io.Copy(hash, file)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash project directory: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAction(list []Action, action Action) bool {
+	for _, a := range list {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// sameVersion reports whether a and b are equal with missing components treated as 0, so that 0.1 equals 0.1.0.
+func sameVersion(a, b versions.Version) bool {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func containsVersion(list []versions.Version, version versions.Version) bool {
+	for _, v := range list {
+		if sameVersion(v, version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/code-game-project/cli-utils/internal/testutil"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+const verifyLangModules = `{
+	"go": {
+		"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+		"library_to_module_versions": {"client": {"0.1": "0.1.0"}},
+		"codegame_to_library_versions": {"client": {"0.9": "0.1"}}
+	}
+}`
+
+func TestVerify(t *testing.T) {
+	modulePath := testutil.BuildFakeModule(t)
+
+	report := Verify(modulePath, VerifyOptions{
+		Lang:     "go",
+		GameURL:  "play.example.com",
+		Registry: newTestRegistry(t, verifyLangModules),
+	})
+	if !report.Passed() {
+		t.Errorf("checks = %+v, want all passed", report.Checks)
+	}
+	want := []string{"info", "version", "actions", "project_types", "library_versions", "registry",
+		"client/create", "client/codegame_file", "client/update", "client/update_idempotent"}
+	if len(report.Checks) != len(want) {
+		t.Fatalf("checks = %+v, want %v", report.Checks, want)
+	}
+	for i, c := range report.Checks {
+		if c.Name != want[i] {
+			t.Errorf("check %d = %s, want %s", i, c.Name, want[i])
+		}
+	}
+}
+
+func TestVerify_broken(t *testing.T) {
+	modulePath := testutil.BuildFakeModule(t)
+	t.Setenv("FAKEMODULE_BROKEN", "1")
+
+	report := Verify(modulePath, VerifyOptions{
+		Lang:    "go",
+		GameURL: "play.example.com",
+		// the module does not support library version 0.2
+		Registry: newTestRegistry(t, `{
+			"go": {
+				"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+				"library_to_module_versions": {"client": {"0.1": "0.1.0", "0.2": "0.1.0"}},
+				"codegame_to_library_versions": {"client": {"0.9": "0.2"}}
+			}
+		}`),
+	})
+	if report.Passed() {
+		t.Fatal("report passed, want failed checks")
+	}
+	failed := make(map[string]bool)
+	for _, c := range report.Checks {
+		if !c.Passed {
+			failed[c.Name] = true
+		}
+	}
+	for _, name := range []string{"registry", "client/codegame_file", "client/update_idempotent"} {
+		if !failed[name] {
+			t.Errorf("check %s passed, want failed (checks: %+v)", name, report.Checks)
+		}
+	}
+	if len(failed) != 3 {
+		t.Errorf("failed checks = %v, want 3", failed)
+	}
+}
+
+func Test_verifyRegistry_shortModuleVersion(t *testing.T) {
+	// 0.1 must match the module version 0.1.0
+	registry := newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.1": "0.1", "0.2": "0.1"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.2"}}
+		}
+	}`)
+	info := ModuleInfo{
+		Version:         versions.MustParse("0.1.0"),
+		LibraryVersions: map[string][]versions.Version{"client": {versions.MustParse("0.1.0")}},
+	}
+
+	err := verifyRegistry(info, registry, "go")
+	want := "client library version 0.2 is mapped to module version 0.1 but not supported by the module"
+	if err == nil || err.Error() != want {
+		t.Errorf("verifyRegistry = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,107 @@
+package modules
+
+import (
+	"errors"
+
+	"github.com/code-game-project/cli-utils/feedback"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// VersionMapping is one link of the CodeGame version -> library version -> module version chain.
+type VersionMapping struct {
+	CodeGameVersion versions.Version
+	LibraryVersion  versions.Version
+	// nil if no module version supports the library version
+	ModuleVersion versions.Version
+}
+
+func (m *Module) versionMaps(projectType string) (cgToLib, libToMod map[string]string) {
+	return m.cgToLibVersions[projectType], m.libToModVersions[projectType]
+}
+
+// ProjectTypes returns the sorted names of the project types supported by the module.
+// Only project types with CodeGame to library version mappings are supported.
+func (m *Module) ProjectTypes() []string {
+	return sortedProjectTypes(m.cgToLibVersions)
+}
+
+// CodeGameVersions returns the CodeGame versions supported by the module for projectType in ascending order.
+func (m *Module) CodeGameVersions(projectType string) []versions.Version {
+	cgToLib, _ := m.versionMaps(projectType)
+	return parseVersionKeys(cgToLib)
+}
+
+// LibraryVersions returns the library versions for projectType which are supported by at least one module version in ascending order.
+func (m *Module) LibraryVersions(projectType string) []versions.Version {
+	_, libToMod := m.versionMaps(projectType)
+	return parseVersionKeys(libToMod)
+}
+
+// ModuleVersions returns the module versions for projectType known to the registry in ascending order.
+func (m *Module) ModuleVersions(projectType string) []versions.Version {
+	_, libToMod := m.versionMaps(projectType)
+	return parseVersionValues(libToMod)
+}
+
+// InstalledVersions returns the installed module versions in ascending order.
+func (m *Module) InstalledVersions() []versions.Version {
+	m.mu.Lock()
+	installed := make(map[string]string, len(m.installedExecutables))
+	for v := range m.installedExecutables {
+		installed[v] = v
+	}
+	m.mu.Unlock()
+	return parseVersionKeys(installed)
+}
+
+// VersionChain returns the library and module versions used for every supported CodeGame version of projectType
+// in ascending order of the CodeGame versions.
+func (m *Module) VersionChain(projectType string) ([]VersionMapping, error) {
+	cgToLib, libToMod := m.versionMaps(projectType)
+	if cgToLib == nil {
+		return nil, ErrUnsupportedProjectType
+	}
+
+	cgVersions := parseVersionKeys(cgToLib)
+	chain := make([]VersionMapping, 0, len(cgVersions))
+	for _, cgVersion := range cgVersions {
+		libVersion, err := versions.Parse(cgToLib[cgVersion.String()])
+		if err != nil {
+			feedback.Warn(FeedbackPkg, "Invalid library version for CodeGame version %s: %s", cgVersion, cgToLib[cgVersion.String()])
+			continue
+		}
+		mapping := VersionMapping{
+			CodeGameVersion: cgVersion,
+			LibraryVersion:  libVersion,
+		}
+		mapping.ModuleVersion, err = versions.FindCompatibleInMap(libVersion, libToMod)
+		if err != nil && !errors.Is(err, versions.ErrNoCompatibleVersion) {
+			return nil, err
+		}
+		chain = append(chain, mapping)
+	}
+	return chain, nil
+}
+
+func parseVersionKeys(versionMap map[string]string) []versions.Version {
+	result := make([]versions.Version, 0, len(versionMap))
+	for v := range versionMap {
+		version, err := versions.Parse(v)
+		if err != nil {
+			feedback.Warn(FeedbackPkg, "Invalid version in version map: %s", v)
+			continue
+		}
+		result = append(result, version)
+	}
+	versions.Sort(result)
+	return result
+}
+
+// parseVersionValues returns the distinct values of versionMap.
+func parseVersionValues(versionMap map[string]string) []versions.Version {
+	values := make(map[string]string, len(versionMap))
+	for _, v := range versionMap {
+		values[v] = v
+	}
+	return parseVersionKeys(values)
+}
@@ -0,0 +1,185 @@
+package modules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func TestVersionViews(t *testing.T) {
+	registry := newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.4", "0.10": "0.5", "0.11": "0.5"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.10", "0.8": "0.9"}, "server": {"0.9": "0.3"}}
+		}
+	}`)
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+
+	if types := m.ProjectTypes(); strings.Join(types, " ") != "client server" {
+		t.Errorf("ProjectTypes = %v, want client and server", types)
+	}
+	if got := versionStrings(m.CodeGameVersions(ProjectTypeClient)); got != "0.8 0.9" {
+		t.Errorf("CodeGameVersions = %s, want 0.8 0.9", got)
+	}
+	if got := versionStrings(m.LibraryVersions(ProjectTypeClient)); got != "0.9 0.10 0.11" {
+		t.Errorf("LibraryVersions = %s, want 0.9 0.10 0.11", got)
+	}
+	if got := versionStrings(m.ModuleVersions(ProjectTypeClient)); got != "0.4 0.5" {
+		t.Errorf("ModuleVersions = %s, want 0.4 0.5", got)
+	}
+
+	chain, err := m.VersionChain(ProjectTypeClient)
+	if err != nil {
+		t.Fatalf("VersionChain: %s", err)
+	}
+	if len(chain) != 2 || chain[0].ModuleVersion.String() != "0.4" || chain[1].LibraryVersion.String() != "0.10" || chain[1].ModuleVersion.String() != "0.5" {
+		t.Errorf("VersionChain(client) = %v", chain)
+	}
+
+	chain, err = m.VersionChain(ProjectTypeServer)
+	if err != nil {
+		t.Fatalf("VersionChain: %s", err)
+	}
+	if len(chain) != 1 || chain[0].ModuleVersion != nil {
+		t.Errorf("VersionChain(server) = %v, want one mapping without module version", chain)
+	}
+}
+
+func versionStrings(vs []versions.Version) string {
+	strs := make([]string, len(vs))
+	for i, v := range vs {
+		strs[i] = v.String()
+	}
+	return strings.Join(strs, " ")
+}
+
+func TestCustomProjectTypes(t *testing.T) {
+	registry := newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.4"}, "spectator": {"0.2": "0.4", "0.3": "0.5"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}, "spectator": {"0.8": "0.2", "0.9": "0.3"}, "bot-library": null}
+		}
+	}`)
+	if langs := registry.AvailableLanguages(); strings.Join(langs["go"].ProjectTypes, " ") != "client spectator" || langs["go"].SupportsServer {
+		t.Errorf("AvailableLanguages = %v, want client and spectator", langs)
+	}
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	if types := strings.Join(m.ProjectTypes(), " "); types != "client spectator" {
+		t.Errorf("ProjectTypes = %s, want client spectator", types)
+	}
+
+	modVersion, libVersion, err := m.projectVersions("spectator", versions.MustParse("0.9"))
+	if err != nil {
+		t.Fatalf("projectVersions: %s", err)
+	}
+	if modVersion.String() != "0.5" || libVersion.String() != "0.3" {
+		t.Errorf("projectVersions(spectator, 0.9) = %s, %s, want 0.5, 0.3", modVersion, libVersion)
+	}
+	_, _, err = m.projectVersions("bot-library", versions.MustParse("0.9"))
+	if !errors.Is(err, ErrUnsupportedProjectType) {
+		t.Errorf("projectVersions(bot-library) error = %v, want ErrUnsupportedProjectType", err)
+	}
+
+	data := projectCreateData("spectator", "game", "go", libVersion)
+	if data.ProjectType != ProjectType_CUSTOM || ProjectTypeName(data.ProjectType, data.ProjectTypeName) != "spectator" {
+		t.Errorf("create data project type = %s (%s), want custom spectator", data.ProjectType, data.GetProjectTypeName())
+	}
+	if data := projectCreateData(ProjectTypeClient, "game", "go", nil); data.ProjectType != ProjectType_CLIENT || data.ProjectTypeName != nil {
+		t.Errorf("create data project type = %s (%s), want client", data.ProjectType, data.GetProjectTypeName())
+	}
+
+	_, err = newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"Client": {"0.9": "0.4"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+		}
+	}`).LoadModule("go")
+	if err == nil || !strings.Contains(err.Error(), "field 'library_to_module_versions.Client': invalid project type name") {
+		t.Errorf("LoadModule with invalid project type error = %v", err)
+	}
+}
+
+func TestModule_projectVersions_server(t *testing.T) {
+	registry := newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.4"}, "server": {"0.2": "0.5", "0.3": "0.6"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}, "server": {"0.8": "0.2", "0.9": "0.3"}}
+		}
+	}`)
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	tests := []struct {
+		cgVersion      versions.Version
+		wantModVersion string
+		wantLibVersion versions.Version
+	}{
+		{versions.MustParse("0.8"), "0.5", versions.MustParse("0.2")},
+		{versions.MustParse("0.9"), "0.6", versions.MustParse("0.3")},
+		{nil, "0.6", nil},
+	}
+	for _, tt := range tests {
+		modVersion, libVersion, err := m.projectVersions(ProjectTypeServer, tt.cgVersion)
+		if err != nil {
+			t.Fatalf("projectVersions(%s): %s", tt.cgVersion, err)
+		}
+		if modVersion.String() != tt.wantModVersion || libVersion.String() != tt.wantLibVersion.String() {
+			t.Errorf("projectVersions(%s) = %s, %s, want %s, %s", tt.cgVersion, modVersion, libVersion, tt.wantModVersion, tt.wantLibVersion)
+		}
+	}
+}
+
+func Test_checkProjectType(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        ModuleInfo
+		projectType string
+		wantErr     bool
+	}{
+		{"client", ModuleInfo{ProjectTypes: []string{"client"}}, "client", false},
+		{"unlisted", ModuleInfo{ProjectTypes: []string{"client"}, ProtocolVersion: ProtocolVersion}, "server", true},
+		{"custom", ModuleInfo{ProjectTypes: []string{"client", "spectator"}, ProtocolVersion: 3}, "spectator", false},
+		{"custom old protocol", ModuleInfo{ProjectTypes: []string{"client", "spectator"}, ProtocolVersion: 2}, "spectator", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProjectType(tt.info, tt.projectType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkProjectType error = %v, want error: %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedProjectType) {
+				t.Errorf("checkProjectType error = %v, want ErrUnsupportedProjectType", err)
+			}
+		})
+	}
+}
+
+func TestModule_ProjectTypes_libraryVersionsOnly(t *testing.T) {
+	registry := newTestRegistry(t, `{
+		"go": {
+			"source": {"provider": "github", "owner": "code-game-project", "repository": "go-module"},
+			"library_to_module_versions": {"client": {"0.9": "0.4"}, "spectator": {"0.2": "0.4"}},
+			"codegame_to_library_versions": {"client": {"0.9": "0.9"}}
+		}
+	}`)
+	m, err := registry.LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	if types := strings.Join(m.ProjectTypes(), " "); types != "client" {
+		t.Errorf("ProjectTypes = %s, want client", types)
+	}
+}
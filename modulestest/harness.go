@@ -0,0 +1,199 @@
+// Package modulestest runs module executables against the contract expected by codegame-cli.
+package modulestest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/modules"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+// Harness executes the actions of a module inside of a temporary project directory.
+type Harness struct {
+	t          testing.TB
+	ModulePath string
+	// Dir is the project directory the actions are executed in.
+	Dir  string
+	Info modules.ModuleInfo
+}
+
+// Result contains the output of an executed action.
+type Result struct {
+	Action   modules.Action
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// nil if the module did not write an action result
+	ActionResult *modules.ActionResult
+}
+
+// New executes the info action of the module at modulePath and fails the test if the response is invalid.
+func New(t testing.TB, modulePath string) *Harness {
+	t.Helper()
+	info, err := modules.ExecModuleInfo(modulePath)
+	if err != nil {
+		t.Fatalf("info: %s", err)
+	}
+	return &Harness{
+		t:          t,
+		ModulePath: modulePath,
+		Dir:        t.TempDir(),
+		Info:       info,
+	}
+}
+
+// Run executes action with actionData (may be nil) and stdin in the project directory.
+func (h *Harness) Run(action modules.Action, actionData proto.Message, stdin string) Result {
+	h.t.Helper()
+	var stdout, stderr bytes.Buffer
+	actionResult, err := modules.ExecAction(h.ModulePath, h.Info, action, actionData, modules.ExecOptions{
+		Dir:    h.Dir,
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	result := Result{
+		Action:       action,
+		Stdout:       stdout.String(),
+		Stderr:       stderr.String(),
+		ActionResult: actionResult,
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil && !errors.Is(err, modules.ErrActionFailed) {
+		h.t.Fatalf("%s: %s", action, err)
+	}
+	return result
+}
+
+func (h *Harness) Create(data *modules.ActionCreateData) Result {
+	h.t.Helper()
+	return h.Run(modules.ActionCreate, data, "")
+}
+
+func (h *Harness) Update(data *modules.ActionUpdateData) Result {
+	h.t.Helper()
+	return h.Run(modules.ActionUpdate, data, "")
+}
+
+func (h *Harness) RunClient(data *modules.ActionRunClientData) Result {
+	h.t.Helper()
+	return h.Run(modules.ActionRunClient, data, "")
+}
+
+func (h *Harness) RunServer(data *modules.ActionRunServerData) Result {
+	h.t.Helper()
+	return h.Run(modules.ActionRunServer, data, "")
+}
+
+func (h *Harness) Test(data *modules.ActionTestData) Result {
+	h.t.Helper()
+	return h.Run(modules.ActionTest, data, "")
+}
+
+func (h *Harness) Build() Result {
+	h.t.Helper()
+	return h.Run(modules.ActionBuild, nil, "")
+}
+
+// SupportsAction returns true if the module lists action in its info response.
+func (h *Harness) SupportsAction(action modules.Action) bool {
+	for _, a := range h.Info.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertSuccess fails the test if the action exited with a non-zero exit code or reported an error in its action result.
+func (h *Harness) AssertSuccess(result Result) {
+	h.t.Helper()
+	h.AssertExitCode(result, 0)
+	if result.ActionResult.GetError() != "" {
+		h.t.Errorf("%s: action result error: %s", result.Action, result.ActionResult.GetError())
+	}
+}
+
+func (h *Harness) AssertExitCode(result Result, code int) {
+	h.t.Helper()
+	if result.ExitCode != code {
+		h.t.Errorf("%s: exit code = %d, want %d\nstderr:\n%s", result.Action, result.ExitCode, code, result.Stderr)
+	}
+}
+
+// AssertFileExists fails the test if path (relative to the project directory) is not a file.
+func (h *Harness) AssertFileExists(path string) {
+	h.t.Helper()
+	stat, err := os.Stat(filepath.Join(h.Dir, path))
+	if err != nil {
+		h.t.Errorf("expected file '%s': %s", path, err)
+	} else if stat.IsDir() {
+		h.t.Errorf("expected file '%s', found directory", path)
+	}
+}
+
+// AssertNoFile fails the test if path (relative to the project directory) exists.
+func (h *Harness) AssertNoFile(path string) {
+	h.t.Helper()
+	if _, err := os.Stat(filepath.Join(h.Dir, path)); err == nil {
+		h.t.Errorf("unexpected file '%s'", path)
+	}
+}
+
+// AssertFileContains fails the test if the file at path (relative to the project directory) does not contain substr.
+func (h *Harness) AssertFileContains(path, substr string) {
+	h.t.Helper()
+	data, err := os.ReadFile(filepath.Join(h.Dir, path))
+	if err != nil {
+		h.t.Errorf("read '%s': %s", path, err)
+		return
+	}
+	if !strings.Contains(string(data), substr) {
+		h.t.Errorf("'%s' does not contain '%s'", path, substr)
+	}
+}
+
+// CodeGameFile loads the .codegame.json file of the project directory and fails the test if it is missing or invalid.
+func (h *Harness) CodeGameFile() *cgfile.CodeGameFileData {
+	h.t.Helper()
+	data, err := cgfile.Load(h.Dir)
+	if err != nil {
+		h.t.Fatalf("load .codegame.json: %s", err)
+	}
+	return data
+}
+
+// AssertCodeGameFile fails the test if a non-zero field of want differs from the .codegame.json file of the project directory.
+func (h *Harness) AssertCodeGameFile(want cgfile.CodeGameFileData) {
+	h.t.Helper()
+	got := h.CodeGameFile()
+	check := func(field, got, want string) {
+		if want != "" && got != want {
+			h.t.Errorf(".codegame.json: %s = '%s', want '%s'", field, got, want)
+		}
+	}
+	check("game_name", got.GameName, want.GameName)
+	check("game_version", got.GameVersion, want.GameVersion)
+	check("project_type", got.ProjectType, want.ProjectType)
+	check("language", got.Language, want.Language)
+	check("game_url", got.GameURL, want.GameURL)
+	if want.ModVersion != nil && versions.Compare(got.ModVersion, want.ModVersion) != 0 {
+		h.t.Errorf(".codegame.json: mod_version = '%s', want '%s'", got.ModVersion, want.ModVersion)
+	}
+	for key, value := range want.LangConfig {
+		if _, ok := got.LangConfig[key]; !ok {
+			h.t.Errorf(".codegame.json: missing lang_config.%s, want '%v'", key, value)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package modulestest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/internal/testutil"
+	"github.com/code-game-project/cli-utils/modules"
+	"github.com/code-game-project/cli-utils/versions"
+)
+
+func TestHarness(t *testing.T) {
+	h := New(t, testutil.BuildFakeModule(t))
+
+	if !h.SupportsAction(modules.ActionCreate) {
+		t.Fatalf("create action not supported")
+	}
+
+	gameURL := "play.example.com"
+	libraryVersion := "0.1"
+	h.AssertSuccess(h.Create(&modules.ActionCreateData{
+		ProjectType:    modules.ProjectType_CLIENT,
+		Language:       "go",
+		GameName:       "test",
+		GameURL:        &gameURL,
+		LibraryVersion: &libraryVersion,
+	}))
+	h.AssertFileContains("main.txt", "library 0.1")
+	h.AssertCodeGameFile(cgfile.CodeGameFileData{
+		GameName:    "test",
+		ProjectType: "client",
+		Language:    "go",
+		GameURL:     gameURL,
+	})
+
+	newLibraryVersion := "0.2"
+	result := h.Update(&modules.ActionUpdateData{
+		ProjectType:    modules.ProjectType_CLIENT,
+		Language:       "go",
+		GameURL:        &gameURL,
+		LibraryVersion: &newLibraryVersion,
+		DryRun:         true,
+	})
+	h.AssertSuccess(result)
+	h.AssertFileContains("main.txt", "library 0.1")
+	if changes := result.ActionResult.GetPlannedChanges(); len(changes) != 1 || changes[0].Path != "main.txt" || !changes[0].HasEffect(h.Dir) {
+		t.Errorf("planned changes = %v, want a write to main.txt", changes)
+	}
+
+	h.AssertExitCode(h.Build(), 1)
+	h.AssertNoFile("build")
+}
+
+func TestHarness_Test(t *testing.T) {
+	h := New(t, testutil.BuildFakeModule(t))
+	if !h.SupportsAction(modules.ActionTest) {
+		t.Fatalf("test action not supported")
+	}
+
+	filter := "Update"
+	result := h.Test(&modules.ActionTestData{
+		ProjectType: modules.ProjectType_CLIENT,
+		Language:    "go",
+		Filter:      &filter,
+		Verbosity:   modules.TestVerbosity_VERBOSE,
+	})
+	h.AssertSuccess(result)
+	if want := `client go tests (filter: "Update", verbosity: VERBOSE)`; !strings.Contains(result.Stdout, want) {
+		t.Errorf("stdout = %q, want %q", result.Stdout, want)
+	}
+	if results := result.ActionResult.GetTestResults(); results.GetPassed() != 1 || results.GetSkipped() != 1 || results.GetFailed() != 0 {
+		t.Errorf("test results = %v, want 1 passed and 1 skipped", results)
+	}
+
+	result = h.Test(&modules.ActionTestData{
+		ProjectType: modules.ProjectType_CLIENT,
+		Language:    "go",
+		Args:        []string{"fail"},
+	})
+	h.AssertExitCode(result, 1)
+	if results := result.ActionResult.GetTestResults(); results.GetFailed() != 1 || strings.Join(results.GetFailedTests(), " ") != "TestUpdate" {
+		t.Errorf("test results = %v, want failed TestUpdate", results)
+	}
+}
+
+func TestModule_ExecTest(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "lang_modules.json")
+	err := os.WriteFile(configPath, []byte(fmt.Sprintf(`{
+		"go": {
+			"source": {"provider": "local", "path": %q},
+			"codegame_to_library_versions": {"client": {"0.9": "0.1"}}
+		}
+	}`, testutil.BuildFakeModule(t))), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := modules.NewRegistry(configPath).LoadModule("go")
+	if err != nil {
+		t.Fatalf("LoadModule: %s", err)
+	}
+	modVersion := versions.MustParse("0.1.0")
+
+	results, err := m.ExecTest(modVersion, modules.ProjectTypeClient, "go", nil, modules.TestVerbosity_QUIET, nil)
+	if err != nil {
+		t.Fatalf("ExecTest: %s", err)
+	}
+	if results.GetPassed() != 2 || results.GetFailed() != 0 {
+		t.Errorf("test results = %v, want 2 passed", results)
+	}
+
+	results, err = m.ExecTest(modVersion, modules.ProjectTypeClient, "go", nil, modules.TestVerbosity_QUIET, []string{"fail"})
+	if !errors.Is(err, modules.ErrActionFailed) {
+		t.Errorf("ExecTest error = %v, want ErrActionFailed", err)
+	}
+	if results.GetPassed() != 1 || results.GetFailed() != 1 || strings.Join(results.GetFailedTests(), " ") != "TestUpdate" {
+		t.Errorf("test results = %v, want failed TestUpdate", results)
+	}
+
+	_, err = m.ExecTest(modVersion, modules.ProjectTypeServer, "go", nil, modules.TestVerbosity_QUIET, nil)
+	if !errors.Is(err, modules.ErrUnsupportedProjectType) {
+		t.Errorf("ExecTest for server error = %v, want ErrUnsupportedProjectType", err)
+	}
+}
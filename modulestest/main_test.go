@@ -0,0 +1,11 @@
+package modulestest
+
+import (
+	"testing"
+
+	"github.com/code-game-project/cli-utils/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Main(m)
+}
@@ -0,0 +1,148 @@
+// fakemodule is a minimal module used to test the module tooling.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/cli-utils/cgfile"
+	"github.com/code-game-project/cli-utils/modules"
+)
+
+// If FAKEMODULE_BROKEN is set, the create and update actions violate the module protocol.
+var broken = os.Getenv("FAKEMODULE_BROKEN") != ""
+
+// FAKEMODULE_PROTOCOL_VERSION overrides the protocol version in the info response. 0 omits the field.
+var protocolVersion = os.Getenv("FAKEMODULE_PROTOCOL_VERSION")
+
+func main() {
+	if len(os.Args) < 2 {
+		os.Exit(1)
+	}
+	switch modules.Action(os.Args[1]) {
+	case modules.ActionInfo:
+		info := map[string]any{
+			"version":            "0.1.0",
+			"actions":            []modules.Action{modules.ActionInfo, modules.ActionCreate, modules.ActionUpdate, modules.ActionRunClient, modules.ActionTest},
+			"library_versions":   map[string][]string{"client": {"0.1"}},
+			"project_types":      []string{"client"},
+			"action_data_format": modules.ActionDataFormatJSON,
+			"protocol_version":   modules.ProtocolVersion,
+		}
+		if protocolVersion == "0" {
+			delete(info, "protocol_version")
+		} else if protocolVersion != "" {
+			info["protocol_version"] = json.Number(protocolVersion)
+		}
+		json.NewEncoder(os.Stdout).Encode(info)
+	case modules.ActionCreate:
+		data := modules.GetCreateData()
+		gameName := data.GameName
+		if broken {
+			gameName = "broken"
+		}
+		err := (&cgfile.CodeGameFileData{
+			GameName:    gameName,
+			ProjectType: modules.ProjectTypeName(data.ProjectType, data.ProjectTypeName),
+			Language:    data.Language,
+			GameURL:     data.GetGameURL(),
+		}).Write(".")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		err = os.WriteFile("main.txt", []byte("library "+data.GetLibraryVersion()+"\n"), 0o644)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case modules.ActionUpdate:
+		data := modules.GetUpdateData()
+		content := []byte("library " + data.GetLibraryVersion() + "\n")
+		if data.DryRun {
+			err := modules.WriteActionResult(&modules.ActionResult{
+				PlannedChanges: []*modules.FileChange{modules.PlannedWrite("main.txt", content)},
+			})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if broken {
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+		file, err := os.OpenFile("main.txt", flags, 0o644)
+		if err == nil {
+			_, err = file.Write(content)
+			file.Close()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case modules.ActionRunClient:
+		data := modules.GetRunClientData()
+		fmt.Printf("player %s %s\n", data.GetPlayerID(), strings.Join(data.Args, " "))
+		if len(data.Args) > 0 && data.Args[0] == "prompt" {
+			// ask the user over the feedback channel
+			f, err := modules.NewModuleFeedback()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			yes, err := f.YesNo("Continue?", true)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Printf("continue %t\n", yes)
+		}
+		if len(data.Args) > 0 && data.Args[0] == "block" {
+			// simulate a game client started by the module, which keeps the output open
+			client := exec.Command(os.Args[0], "sleep")
+			client.Stdout = os.Stdout
+			client.Stderr = os.Stderr
+			client.Start()
+			client.Wait()
+		}
+	case modules.ActionTest:
+		data := modules.GetTestData()
+		fmt.Printf("%s %s tests (filter: %q, verbosity: %s)\n", modules.ProjectTypeName(data.ProjectType, data.ProjectTypeName), data.Language, data.GetFilter(), data.Verbosity)
+		// the "fail" arg lets TestUpdate fail
+		results := &modules.TestResults{}
+		for _, name := range []string{"TestCreate", "TestUpdate"} {
+			if !strings.Contains(name, data.GetFilter()) {
+				results.Skipped++
+			} else if name == "TestUpdate" && len(data.Args) > 0 && data.Args[0] == "fail" {
+				results.Failed++
+				results.FailedTests = append(results.FailedTests, name)
+			} else {
+				results.Passed++
+			}
+		}
+		result := &modules.ActionResult{TestResults: results}
+		if results.Failed > 0 {
+			errMsg := fmt.Sprintf("%d tests failed", results.Failed)
+			result.Error = &errMsg
+		}
+		err := modules.WriteActionResult(result)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if results.Failed > 0 {
+			os.Exit(1)
+		}
+	case "sleep":
+		time.Sleep(time.Hour)
+	default:
+		fmt.Fprintf(os.Stderr, "unsupported action: %s\n", os.Args[1])
+		os.Exit(1)
+	}
+}
@@ -2,7 +2,9 @@ package request
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -82,8 +85,17 @@ func (r *reader) Close() error {
 }
 
 func Fetch(url, method string, cacheMaxAge time.Duration, timeout time.Duration, reportProgress bool, body io.Reader) (responseBody io.ReadCloser, statusCode int, err error) {
+	return FetchWithHeader(url, method, nil, cacheMaxAge, timeout, reportProgress, body)
+}
+
+// FetchWithHeader is like Fetch but adds header to the request.
+// header is not sent to other hosts if the request is redirected.
+// Responses are cached separately for every header, so that responses to requests with credentials
+// are never returned for requests without them.
+func FetchWithHeader(url, method string, header http.Header, cacheMaxAge time.Duration, timeout time.Duration, reportProgress bool, body io.Reader) (responseBody io.ReadCloser, statusCode int, err error) {
 	feedback.Debug(FeedbackPkg, "Fetching %s %s...", strings.ToUpper(method), url)
-	cacheFilePath := filepath.Join(httpCacheDir, neturl.PathEscape(url))
+	key := cacheKey(url, header)
+	cacheFilePath := filepath.Join(httpCacheDir, key)
 	if cacheMaxAge > 0 {
 		if stat, err := os.Stat(cacheFilePath); err == nil && time.Since(stat.ModTime()) <= cacheMaxAge {
 			file, err := os.Open(cacheFilePath)
@@ -98,11 +110,27 @@ func Fetch(url, method string, cacheMaxAge time.Duration, timeout time.Duration,
 	if err != nil {
 		return nil, 0, fmt.Errorf("create http request: %w", err)
 	}
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
 	if _, err = os.Stat(cacheFilePath); err == nil {
-		loadETag(url, req)
+		loadETag(key, req)
 	}
 	client := &http.Client{
 		Timeout: timeout,
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if r.URL.Host != via[0].URL.Host {
+				for name := range header {
+					r.Header.Del(name)
+				}
+			}
+			return nil
+		},
 	}
 	resp, err := client.Do(req)
 	if err != nil || resp.StatusCode == http.StatusNotModified {
@@ -116,14 +144,14 @@ func Fetch(url, method string, cacheMaxAge time.Duration, timeout time.Duration,
 			}
 			return file, 0, nil
 		}
-		os.Remove(filepath.Join(etagCacheDir, neturl.PathEscape(url)))
+		os.Remove(filepath.Join(etagCacheDir, key))
 		return nil, 0, fmt.Errorf("fetch data: %w", err)
 	}
 	if resp.StatusCode >= 300 {
 		statusCode = resp.StatusCode
 		cacheMaxAge = 0
 	} else {
-		saveETag(url, resp)
+		saveETag(key, resp)
 	}
 
 	var cache io.WriteCloser
@@ -157,6 +185,24 @@ func Fetch(url, method string, cacheMaxAge time.Duration, timeout time.Duration,
 	}, statusCode, nil
 }
 
+// cacheKey returns the name of the cache files of a request to url with header.
+func cacheKey(url string, header http.Header) string {
+	key := neturl.PathEscape(url)
+	if len(header) == 0 {
+		return key
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s: %q\n", name, header.Values(name))
+	}
+	return key + "." + hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
 func FetchFile(url string, cacheMaxAge time.Duration, reportProgress bool) (io.ReadCloser, error) {
 	body, status, err := Fetch(url, "GET", cacheMaxAge, 0, reportProgress, nil)
 	if err != nil {
@@ -170,8 +216,13 @@ func FetchFile(url string, cacheMaxAge time.Duration, reportProgress bool) (io.R
 }
 
 func FetchJSON[T any](url string, maxCacheAge time.Duration) (T, error) {
+	return FetchJSONWithHeader[T](url, nil, maxCacheAge)
+}
+
+// FetchJSONWithHeader is like FetchJSON but adds header to the request.
+func FetchJSONWithHeader[T any](url string, header http.Header, maxCacheAge time.Duration) (T, error) {
 	var obj T
-	file, status, err := Fetch(url, "GET", maxCacheAge, 10*time.Second, false, nil)
+	file, status, err := FetchWithHeader(url, "GET", header, maxCacheAge, 10*time.Second, false, nil)
 	if err != nil && !errors.Is(err, io.EOF) {
 		return obj, err
 	}
@@ -280,7 +331,7 @@ func HasContentType(h http.Header, mimetype string) bool {
 	return false
 }
 
-func saveETag(url string, resp *http.Response) error {
+func saveETag(key string, resp *http.Response) error {
 	err := os.MkdirAll(etagCacheDir, 0o755)
 	if err != nil {
 		return fmt.Errorf("create etag cache directory: %w", err)
@@ -291,7 +342,7 @@ func saveETag(url string, resp *http.Response) error {
 		return errNoETag
 	}
 
-	file, err := os.Create(filepath.Join(etagCacheDir, neturl.PathEscape(url)))
+	file, err := os.Create(filepath.Join(etagCacheDir, key))
 	if err != nil {
 		return fmt.Errorf("create etag cache file: %w", err)
 	}
@@ -303,8 +354,8 @@ func saveETag(url string, resp *http.Response) error {
 	return nil
 }
 
-func loadETag(url string, req *http.Request) error {
-	file, err := os.Open(filepath.Join(etagCacheDir, neturl.PathEscape(url)))
+func loadETag(key string, req *http.Request) error {
+	file, err := os.Open(filepath.Join(etagCacheDir, key))
 	if err != nil {
 		return errNoETag
 	}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/code-game-project/cli-utils/request"
@@ -77,6 +79,19 @@ func CreatePlayer(gameURL, gameID, username, joinSecret string) (playerID, playe
 	return resp.PlayerID, resp.PlayerSecret, nil
 }
 
+// DeletePlayer removes the player from the game. Players which do not exist anymore are ignored.
+func DeletePlayer(gameURL, gameID, playerID, playerSecret string) error {
+	body, status, err := request.Fetch(request.BaseURL("http", gameURL)+"/api/games/"+gameID+"/players/"+playerID+"?player_secret="+url.QueryEscape(playerSecret), "DELETE", 0, 10*time.Second, false, nil)
+	if err != nil {
+		return fmt.Errorf("delete player: %w", err)
+	}
+	body.Close()
+	if status >= 300 && status != http.StatusNotFound {
+		return fmt.Errorf("delete player: http status: %s", http.StatusText(status))
+	}
+	return nil
+}
+
 type Game struct {
 	ID        string `json:"id"`
 	Players   int    `json:"players"`
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/adrg/xdg"
 
@@ -27,12 +28,9 @@ type Session struct {
 	PlayerSecret string `json:"player_secret"`
 }
 
+// sessionsPath is created by Save when the first session is saved.
 var sessionsPath = filepath.Join(xdg.DataHome, "codegame", "sessions")
 
-func init() {
-	os.MkdirAll(sessionsPath, 0o755)
-}
-
 func NewSession(gameURL, username, gameID, playerID, playerSecret string) Session {
 	return Session{
 		GameURL:      gameURL,
@@ -47,6 +45,9 @@ func NewSession(gameURL, username, gameID, playerID, playerSecret string) Sessio
 func ListSessions() (map[string][]Session, error) {
 	gameDirs, err := os.ReadDir(filepath.Join(sessionsPath))
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string][]Session), nil
+		}
 		return nil, err
 	}
 
@@ -93,6 +94,9 @@ func ListSessionsByGame(gameURL string) ([]Session, error) {
 func ListGames() ([]string, error) {
 	gameDirs, err := os.ReadDir(filepath.Join(sessionsPath))
 	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make([]string, 0), nil
+		}
 		return nil, err
 	}
 
@@ -146,7 +150,7 @@ func (s Session) Save() error {
 
 // Check returns true if the player still exists in the game.
 func (s Session) Check() (bool, error) {
-	body, status, err := request.Fetch(request.BaseURL("http", s.GameURL)+"/api/games/"+s.GameID+"/players/"+s.PlayerID, "GET", 0, false, nil)
+	body, status, err := request.Fetch(request.BaseURL("http", s.GameURL)+"/api/games/"+s.GameID+"/players/"+s.PlayerID, "GET", 0, 10*time.Second, false, nil)
 	if err != nil {
 		return false, err
 	}
@@ -3,6 +3,7 @@ package versions
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -81,6 +82,13 @@ func Compare(a, b Version) int {
 	return 0
 }
 
+// Sort sorts versions in ascending order.
+func Sort(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) == 1
+	})
+}
+
 // FindCompatibleInMap returns the next best compatible module version in versionsMap (library/protocol version -> application version).
 func FindCompatibleInMap(version Version, versionsMap map[string]string) (Version, error) {
 	if version, ok := versionsMap[version.String()]; ok {
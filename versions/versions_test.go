@@ -124,3 +124,14 @@ func Test_FindCompatibleInMap(t *testing.T) {
 		})
 	}
 }
+
+func Test_Sort(t *testing.T) {
+	vs := []Version{MustParse("1.2"), MustParse("0.9.1"), MustParse("1.10"), MustParse("0.9"), MustParse("1.2.3")}
+	Sort(vs)
+	want := []string{"0.9", "0.9.1", "1.2", "1.2.3", "1.10"}
+	for i, v := range vs {
+		if v.String() != want[i] {
+			t.Fatalf("Sort = %v, want %v", vs, want)
+		}
+	}
+}